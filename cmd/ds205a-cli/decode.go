@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// captureLine representa una línea de un archivo de captura JSONL
+type captureLine struct {
+	Time string `json:"time"` // Marca de tiempo (opcional)
+	Dir  string `json:"dir"`  // Dirección: tx/rx (opcional)
+	Data string `json:"data"` // Bytes de la trama en hexadecimal
+	Hex  string `json:"hex"`  // Alias de data
+}
+
+// cmdDecode decodifica tramas desde un volcado hexadecimal o un archivo de captura
+func cmdDecode(hexInput, file string) error {
+	switch {
+	case hexInput != "":
+		data, err := parseHex(hexInput)
+		if err != nil {
+			return err
+		}
+		printDecodedFrame(1, "", protocol.DecodeFrame(data))
+		return nil
+	case file != "":
+		return decodeFile(file)
+	default:
+		return fmt.Errorf("command '%s' requires -hex <bytes> or -file <capture>", CmdDecode)
+	}
+}
+
+// decodeFile decodifica un archivo con una trama por línea, ya sea en JSON
+// ({"data": "7F 01 ..."}) o como texto hexadecimal plano
+func decodeFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open capture: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	frames := 0
+	invalid := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var label, payload string
+		if strings.HasPrefix(line, "{") {
+			var cl captureLine
+			if err := json.Unmarshal([]byte(line), &cl); err != nil {
+				return fmt.Errorf("line %d: invalid JSON: %w", lineNum, err)
+			}
+			payload = cl.Data
+			if payload == "" {
+				payload = cl.Hex
+			}
+			label = strings.TrimSpace(cl.Time + " " + cl.Dir)
+		} else {
+			payload = line
+		}
+
+		data, err := parseHex(payload)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		frames++
+		decoded := protocol.DecodeFrame(data)
+		if !decoded.Valid() {
+			invalid++
+		}
+		printDecodedFrame(frames, label, decoded)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read capture: %w", err)
+	}
+
+	fmt.Printf("%d frames decoded, %d with validation errors\n", frames, invalid)
+	return nil
+}
+
+// parseHex acepta bytes en formatos comunes de logs: "7F 01 01", "7F0101",
+// "0x7F,0x01" o "7F:01:01"
+func parseHex(s string) ([]byte, error) {
+	replacer := strings.NewReplacer("0x", "", "0X", "", ",", " ", ":", " ", "-", " ", "[", " ", "]", " ")
+	clean := strings.Join(strings.Fields(replacer.Replace(s)), "")
+	if clean == "" {
+		return nil, fmt.Errorf("no hex data")
+	}
+	data, err := hex.DecodeString(clean)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex data: %w", err)
+	}
+	return data, nil
+}
+
+// printDecodedFrame imprime la trama como tabla de campos con sus validaciones
+func printDecodedFrame(index int, label string, frame *protocol.DecodedFrame) {
+	header := fmt.Sprintf("Frame %d: %s, %d bytes", index, frame.Kind, len(frame.Data))
+	if label != "" {
+		header += " (" + label + ")"
+	}
+	fmt.Println(header)
+	fmt.Printf("  Raw: [% 02X]\n", frame.Data)
+
+	if len(frame.Fields) > 0 {
+		fmt.Printf("  %-6s  %-24s  %-10s  %s\n", "Offset", "Field", "Raw", "Value")
+		for _, f := range frame.Fields {
+			fmt.Printf("  %-6d  %-24s  %-10s  %s\n", f.Offset, f.Name, fmt.Sprintf("% 02X", f.Raw), f.Value)
+		}
+	}
+
+	fmt.Println("  Validation:")
+	for _, c := range frame.Checks {
+		result := "OK"
+		if !c.OK {
+			result = "FAIL"
+		}
+		fmt.Printf("    [%-4s] %-10s %s\n", result, c.Name, c.Detail)
+	}
+	fmt.Println()
+}
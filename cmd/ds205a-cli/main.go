@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/dumacp/ds205a/pkg/ds205a"
+	"github.com/dumacp/ds205a/pkg/i18n"
+	"github.com/dumacp/ds205a/pkg/scenario"
 )
 
 // Comandos disponibles
@@ -30,8 +32,14 @@ const (
 	CmdResetRightCounters  Command = "reset-right-counters"
 	CmdSetParams           Command = "set-params"
 	CmdReset               Command = "reset"
+	CmdRunScript           Command = "run-script"
+	CmdDecode              Command = "decode"
+	CmdUpdateCheck         Command = "update-check"
 )
 
+// lang es el idioma de la salida para el técnico (-lang)
+var lang = i18n.English
+
 func main() {
 	var (
 		port     = flag.String("port", "/dev/ttyUSB0", "Serial port")
@@ -42,6 +50,20 @@ func main() {
 		value1   = flag.Int("value1", 1, "Value parameter for commands that require it")
 		value2   = flag.Int("value2", 0, "Value parameter for commands that require it for command (set-params)")
 		verbose  = flag.String("verbose", "warn", "Log level: silent, error, warn, info, debug")
+		logFmt   = flag.String("log-format", "text", "Log format: text, json")
+		logTime  = flag.Bool("log-time", false, "Prefix log lines with a timestamp")
+		version  = flag.Bool("version", false, "Print library and protocol version and exit")
+		entry    = flag.String("entry", "left", "Physical side used as entry: left, right")
+		script   = flag.String("script", "", "YAML scenario file for command (run-script)")
+		hexInput = flag.String("hex", "", "Frame bytes in hex for command (decode), e.g. \"7F 01 01 ...\"")
+		file     = flag.String("file", "", "Capture file (JSONL or one hex frame per line) for command (decode)")
+		lockDir  = flag.String("lock-dir", "", "Directory for the inter-process port lock file, e.g. /var/lock (empty: no lock)")
+		simulate = flag.Bool("simulate", false, "Answer commands with synthetic responses instead of using the serial port (demo mode)")
+		download = flag.String("download", "", "Directory where command (update-check) downloads the latest binary and checks it against the release checksums.txt; this proves integrity, not authenticity (empty: only check)")
+		httpWait = flag.Duration("download-timeout", 5*time.Minute, "Timeout for command (update-check): release query, checksums.txt and binary download")
+		langFlag = flag.String("lang", "en", "Language for command output and hints: en, es")
+		busy     = flag.String("busy", "fail", "When the device reports busy: fail, retry, wait-idle")
+		traceOut = flag.String("trace", "", "Append a one-line-per-transaction trace to this file (\"-\" for stderr)")
 	)
 
 	// Personalizar la salida de ayuda
@@ -60,12 +82,27 @@ func main() {
 		fmt.Printf("  %s -cmd %s -value1 1 -value2 1\n", os.Args[0], CmdSetParams)
 		fmt.Printf("  %s -cmd %s\n", os.Args[0], CmdDisableRestrictions)
 		fmt.Printf("  %s -cmd %s\n", os.Args[0], CmdCloseGate)
+		fmt.Printf("  %s -cmd %s -script commissioning.yaml\n", os.Args[0], CmdRunScript)
+		fmt.Printf("  %s -cmd %s -hex \"7E 00 01 80 01 00 00 FF\"\n", os.Args[0], CmdDecode)
+		fmt.Printf("  %s -cmd %s -download .\n", os.Args[0], CmdUpdateCheck)
 		fmt.Printf("  %s -verbose info -cmd %s    # Enable info logging\n", os.Args[0], CmdStatus)
 		fmt.Printf("  %s -verbose debug -cmd %s   # Enable debug logging (shows TX/RX)\n\n", os.Args[0], CmdStatus)
 	}
 
 	flag.Parse()
 
+	if *version {
+		fmt.Printf("ds205a %s (protocol %s)\n", ds205a.Version(), ds205a.ProtocolRevision())
+		return
+	}
+
+	locale, err := i18n.ParseLocale(*langFlag)
+	if err != nil {
+		fmt.Printf("Invalid language: %s\nValid languages: en, es\n", *langFlag)
+		os.Exit(1)
+	}
+	lang = locale
+
 	if *command == "" {
 		printUsage()
 		os.Exit(1)
@@ -80,6 +117,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	// El análisis de tramas no requiere dispositivo
+	if validCmd == CmdDecode {
+		if err := cmdDecode(*hexInput, *file); err != nil {
+			log.Fatalf("Decode failed: %v", err)
+		}
+		return
+	}
+
+	// La búsqueda de actualizaciones tampoco requiere dispositivo. Usa su
+	// propio timeout: -timeout está pensado para el puerto serie y no
+	// alcanza para descargar un binario
+	if validCmd == CmdUpdateCheck {
+		ctx, cancel := context.WithTimeout(context.Background(), *httpWait)
+		defer cancel()
+		if err := cmdUpdateCheck(ctx, *download); err != nil {
+			log.Fatalf("%s: %v", i18n.Text(lang, i18n.UpdateFailed), err)
+		}
+		return
+	}
+
+	// Cargar el escenario antes de abrir el puerto
+	var sc *scenario.Scenario
+	if validCmd == CmdRunScript {
+		if *script == "" {
+			fmt.Printf("Error: command '%s' requires -script <file>\n", CmdRunScript)
+			os.Exit(1)
+		}
+		sc, err = scenario.Load(*script)
+		if err != nil {
+			log.Fatalf("Error loading scenario: %v", err)
+		}
+	}
+
 	// Parsear nivel de log
 	logLevel := parseLogLevel(*verbose)
 	if logLevel == -1 {
@@ -87,25 +157,97 @@ func main() {
 		os.Exit(1)
 	}
 
+	var format ds205a.LogFormat
+	switch *logFmt {
+	case "text":
+		format = ds205a.LogFormatText
+	case "json":
+		format = ds205a.LogFormatJSON
+	default:
+		fmt.Printf("Invalid log format: %s\nValid formats: text, json\n", *logFmt)
+		os.Exit(1)
+	}
+
+	logger := ds205a.NewLogger(ds205a.LoggerConfig{
+		Level:      ds205a.LogLevel(logLevel),
+		Output:     os.Stdout,
+		Format:     format,
+		Timestamps: *logTime,
+	})
+
+	var orientation ds205a.Orientation
+	switch *entry {
+	case "left":
+		orientation = ds205a.EntryIsLeft
+	case "right":
+		orientation = ds205a.EntryIsRight
+	default:
+		fmt.Printf("Invalid entry side: %s\nValid sides: left, right\n", *entry)
+		os.Exit(1)
+	}
+
 	// Crear dispositivo
-	device, err := ds205a.NewWithLogLevel(*port, byte(*deviceID), *baudRate, *timeout, ds205a.LogLevel(logLevel))
+	opts := []ds205a.Option{ds205a.WithOrientation(orientation)}
+	if *lockDir != "" {
+		opts = append(opts, ds205a.WithPortLock(*lockDir))
+	}
+	if *simulate {
+		opts = append(opts, ds205a.WithSimulatedResponses())
+	}
+	switch *busy {
+	case "fail":
+	case "retry":
+		opts = append(opts, ds205a.WithBusyPolicy(ds205a.BusyRetry, 0))
+	case "wait-idle":
+		opts = append(opts, ds205a.WithBusyPolicy(ds205a.BusyWaitIdle, 0))
+	default:
+		fmt.Printf("Invalid busy policy: %s\nValid policies: fail, retry, wait-idle\n", *busy)
+		os.Exit(1)
+	}
+	switch *traceOut {
+	case "":
+	case "-":
+		opts = append(opts, ds205a.WithTraceWriter(os.Stderr))
+	default:
+		traceFile, err := os.OpenFile(*traceOut, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			log.Fatalf("Error opening trace file: %v", err)
+		}
+		defer traceFile.Close()
+		opts = append(opts, ds205a.WithTraceWriter(traceFile))
+	}
+	device, err := ds205a.NewWithLogger(*port, byte(*deviceID), *baudRate, *timeout, logger, opts...)
 	if err != nil {
 		log.Fatalf("Error creating device: %v", err)
 	}
 
 	// Abrir conexión
 	if err := device.Open(); err != nil {
-		log.Fatalf("Error opening device: %v", err)
+		if hint := ds205a.LocalizedHint(err, lang); hint != "" {
+			log.Fatalf("%s: %v\n%s: %s", i18n.Text(lang, i18n.OpenFailed), err, i18n.Text(lang, i18n.HintLabel), hint)
+		}
+		log.Fatalf("%s: %v", i18n.Text(lang, i18n.OpenFailed), err)
 	}
 	defer device.Close()
 
+	// Los escenarios aplican el timeout por paso, no a toda la ejecución
+	if sc != nil {
+		if err := cmdRunScript(device, sc, *timeout); err != nil {
+			log.Fatalf("Scenario failed: %v", err)
+		}
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
 
 	// Ejecutar comando
 	err = executeCommand(device, Command(*command), *value1, *value2, ctx)
 	if err != nil {
-		log.Fatalf("Command failed: %v", err)
+		if hint := ds205a.LocalizedHint(err, lang); hint != "" {
+			log.Fatalf("%s: %v\n%s: %s", i18n.Text(lang, i18n.CommandFailed), err, i18n.Text(lang, i18n.HintLabel), hint)
+		}
+		log.Fatalf("%s: %v", i18n.Text(lang, i18n.CommandFailed), err)
 	}
 }
 
@@ -150,16 +292,21 @@ func cmdStatus(device *ds205a.Turnstile, ctx context.Context) error {
 		return err
 	}
 
-	fmt.Printf("Turnstile Status:\n")
-	fmt.Printf("  Machine Number: %d\n", status.MachineNumber)
-	fmt.Printf("  Version Number: %d\n", status.VersionNumber)
-	fmt.Printf("  Fault Event: 0x%02X\n", status.FaultEvent)
-	fmt.Printf("  Gate Status: 0x%02X\n", status.GateStatus)
-	fmt.Printf("  Alarm Event: 0x%02X\n", status.AlarmEvent)
-	fmt.Printf("  Infrared Status: 0x%02X\n", status.InfraredStatus)
-	fmt.Printf("  Power Supply Voltage: %d\n", status.PowerSupplyVoltage)
-	fmt.Printf("  Left Pedestrian Count: %d\n", status.LeftPedestrianCount)
-	fmt.Printf("  Right Pedestrian Count: %d\n", status.RightPedestrianCount)
+	fmt.Println(i18n.Text(lang, i18n.StatusTitle))
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.StatusMachine), status.MachineNumber)
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.StatusVersion), status.VersionNumber)
+	fmt.Printf("  %s: 0x%02X\n", i18n.Text(lang, i18n.StatusFault), status.FaultEvent)
+	fmt.Printf("  %s: 0x%02X\n", i18n.Text(lang, i18n.StatusGate), status.GateStatus)
+	fmt.Printf("  %s: %s\n", i18n.Text(lang, i18n.StatusGateState), status.GateState)
+	fmt.Printf("  %s: 0x%02X\n", i18n.Text(lang, i18n.StatusAlarm), status.AlarmEvent)
+	fmt.Printf("  %s: 0x%02X\n", i18n.Text(lang, i18n.StatusInfrared), status.InfraredStatus)
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.StatusVoltage), status.PowerSupplyVoltage)
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.StatusLeftCount), status.LeftPedestrianCount)
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.StatusRightCount), status.RightPedestrianCount)
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.StatusEntryCount), status.EntryCount)
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.StatusExitCount), status.ExitCount)
+	fmt.Printf("  %s: %s\n", i18n.Text(lang, i18n.StatusReceivedAt), status.ReceivedAt.UTC().Format(time.RFC3339Nano))
+	fmt.Printf("  %s: %v\n", i18n.Text(lang, i18n.StatusLatency), status.Latency)
 	return nil
 }
 
@@ -169,72 +316,111 @@ func cmdInfo(device *ds205a.Turnstile, ctx context.Context) error {
 		return err
 	}
 
-	fmt.Printf("Device Information:\n")
-	fmt.Printf("  Version: %d.%d.%d\n", info.Version[0], info.Version[1], info.Version[2])
-	fmt.Printf("  Machine Type: %d\n", info.MachineType)
+	fmt.Println(i18n.Text(lang, i18n.InfoTitle))
+	fmt.Printf("  %s: %d.%d.%d\n", i18n.Text(lang, i18n.InfoVersion), info.Version[0], info.Version[1], info.Version[2])
+	fmt.Printf("  %s: %d\n", i18n.Text(lang, i18n.InfoMachine), info.MachineNumber)
+
+	fp, err := device.Fingerprint(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s: %s\n", i18n.Text(lang, i18n.InfoFingerprint), fp.ID)
+	if len(fp.Quirks) > 0 {
+		fmt.Printf("  %s: %s\n", i18n.Text(lang, i18n.InfoQuirks), strings.Join(fp.Quirks, ", "))
+	}
 	return nil
 }
 
 func cmdLeftOpen(device *ds205a.Turnstile, value uint8, ctx context.Context) error {
-	fmt.Printf("Opening left passage with value %d...\n", value)
-	return device.LeftOpen(ctx, value)
+	fmt.Println(i18n.Text(lang, i18n.CmdLeftOpen, value))
+	return printCommandResult(device.LeftOpen(ctx, value))
 }
 
 func cmdLeftAlwaysOpen(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Setting left passage to always open...")
+	fmt.Println(i18n.Text(lang, i18n.CmdLeftAlwaysOpen))
 	return device.LeftAlwaysOpen(ctx)
 }
 
 func cmdRightOpen(device *ds205a.Turnstile, value uint8, ctx context.Context) error {
-	fmt.Printf("Opening right passage with value %d...\n", value)
-	return device.RightOpen(ctx, value)
+	fmt.Println(i18n.Text(lang, i18n.CmdRightOpen, value))
+	return printCommandResult(device.RightOpen(ctx, value))
 }
 
 func cmdRightAlwaysOpen(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Setting right passage to always open...")
+	fmt.Println(i18n.Text(lang, i18n.CmdRightAlwaysOpen))
 	return device.RightAlwaysOpen(ctx)
 }
 
 func cmdCloseGate(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Closing gate...")
-	return device.CloseGate(ctx)
+	fmt.Println(i18n.Text(lang, i18n.CmdCloseGate))
+	return printCommandResult(device.CloseGate(ctx))
+}
+
+// printCommandResult imprime la confirmación reportada por el dispositivo
+func printCommandResult(result *ds205a.CommandResult, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.Text(lang, i18n.CommandConfirmed, result.Status.GateStatus, result.Latency, result.Attempts))
+	return nil
 }
 
 func cmdForbiddenLeft(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Forbidding left passage...")
+	fmt.Println(i18n.Text(lang, i18n.CmdForbidLeft))
 	return device.ForbiddenLeftPassage(ctx)
 }
 
 func cmdForbiddenRight(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Forbidding right passage...")
+	fmt.Println(i18n.Text(lang, i18n.CmdForbidRight))
 	return device.ForbiddenRightPassage(ctx)
 }
 
 func cmdDisableRestrictions(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Disabling passage restrictions...")
+	fmt.Println(i18n.Text(lang, i18n.CmdDisableRestrictions))
 	return device.DisablePassageRestrictions(ctx)
 }
 
 func cmdResetLeftCounters(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Resetting left counters...")
+	fmt.Println(i18n.Text(lang, i18n.CmdResetLeftCounters))
 	return device.ResetLeftCounters(ctx)
 }
 
 func cmdResetRightCounters(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Resetting right counters...")
+	fmt.Println(i18n.Text(lang, i18n.CmdResetRightCounters))
 	return device.ResetRightCounters(ctx)
 }
 
 func cmdSetParameters(device *ds205a.Turnstile, value1 uint8, value2 uint8, ctx context.Context) error {
-	fmt.Printf("Setting parameters with Menu %d y/o value %d...\n", value1, value2)
+	fmt.Println(i18n.Text(lang, i18n.CmdSetParams, value1, value2))
 	return device.SetParameters(ctx, value1, value2)
 }
 
 func cmdReset(device *ds205a.Turnstile, ctx context.Context) error {
-	fmt.Println("Resetting device...")
+	fmt.Println(i18n.Text(lang, i18n.CmdReset))
 	return device.Reset(ctx)
 }
 
+func cmdRunScript(device *ds205a.Turnstile, sc *scenario.Scenario, stepTimeout time.Duration) error {
+	fmt.Println(i18n.Text(lang, i18n.ScenarioRunning, sc.Name, len(sc.Steps)))
+
+	runner := scenario.NewRunner(device, stepTimeout)
+	runner.OnStep(func(r scenario.StepResult) {
+		if r.Err != nil {
+			fmt.Printf("  [FAIL] %2d. %s (%v): %v\n", r.Index, r.Name, r.Duration.Round(time.Millisecond), r.Err)
+			return
+		}
+		fmt.Printf("  [ OK ] %2d. %s (%v)\n", r.Index, r.Name, r.Duration.Round(time.Millisecond))
+	})
+
+	report, err := runner.Run(context.Background(), sc)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.Text(lang, i18n.ScenarioPassed, len(report.Steps), report.Duration.Round(time.Millisecond)))
+	return nil
+}
+
 // getAvailableCommands retorna la lista de comandos disponibles
 func getAvailableCommands() string {
 	commands := []Command{
@@ -242,7 +428,7 @@ func getAvailableCommands() string {
 		CmdRightOpen, CmdRightAlwaysOpen, CmdCloseGate,
 		CmdForbidLeft, CmdForbidRight, CmdDisableRestrictions,
 		CmdResetLeftCounters, CmdResetRightCounters,
-		CmdSetParams, CmdReset,
+		CmdSetParams, CmdReset, CmdRunScript, CmdDecode, CmdUpdateCheck,
 	}
 
 	var cmdStrs []string
@@ -259,7 +445,7 @@ func isValidCommand(cmd Command) bool {
 		CmdRightOpen, CmdRightAlwaysOpen, CmdCloseGate,
 		CmdForbidLeft, CmdForbidRight, CmdDisableRestrictions,
 		CmdResetLeftCounters, CmdResetRightCounters,
-		CmdSetParams, CmdReset,
+		CmdSetParams, CmdReset, CmdRunScript, CmdDecode, CmdUpdateCheck,
 	}
 
 	for _, validCmd := range validCommands {
@@ -326,6 +512,13 @@ func printCommandsHelp() {
 			{CmdSetParams, "Set device parameters", true},
 			{CmdReset, "Reset device", false},
 		},
+		"Scripting": {
+			{CmdRunScript, "Run a YAML scenario (use -script <file>)", false},
+		},
+		"Diagnostics": {
+			{CmdDecode, "Decode frames offline (use -hex <bytes> or -file <capture>)", false},
+			{CmdUpdateCheck, "Check for a newer release (use -download <dir> to fetch and verify it)", false},
+		},
 	}
 
 	for category, cmds := range commands {
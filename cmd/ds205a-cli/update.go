@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/dumacp/ds205a/pkg/ds205a"
+	"github.com/dumacp/ds205a/pkg/i18n"
+)
+
+// latestReleaseURL es la API de GitHub con la última versión publicada
+const latestReleaseURL = "https://api.github.com/repos/dumacp/ds205a/releases/latest"
+
+// checksumsAsset es el archivo de la versión con los SHA-256 de los binarios
+const checksumsAsset = "checksums.txt"
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// release contiene los campos usados de la API de versiones de GitHub
+type release struct {
+	TagName string         `json:"tag_name"`
+	HTMLURL string         `json:"html_url"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// cmdUpdateCheck consulta la última versión publicada y la compara con la
+// compilada. Si output no está vacío descarga el binario de la plataforma
+// actual en ese directorio y verifica su SHA-256 contra checksums.txt.
+// checksums.txt viene de la misma versión que el binario: detecta descargas
+// corruptas o truncadas, pero no prueba quién publicó el binario
+func cmdUpdateCheck(ctx context.Context, output string) error {
+	rel, err := fetchLatestRelease(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := ds205a.Version()
+	fmt.Println(i18n.Text(lang, i18n.UpdateCurrent, current))
+	fmt.Println(i18n.Text(lang, i18n.UpdateLatest, rel.TagName, rel.HTMLURL))
+
+	switch cmp, ok := compareVersions(current, rel.TagName); {
+	case !ok:
+		fmt.Println(i18n.Text(lang, i18n.UpdateNotComparable))
+	case cmp < 0:
+		fmt.Println(i18n.Text(lang, i18n.UpdateAvailable))
+	default:
+		fmt.Println(i18n.Text(lang, i18n.UpdateUpToDate))
+	}
+
+	if output == "" {
+		return nil
+	}
+
+	asset, ok := platformAsset(rel.Assets, runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sums, ok := findAsset(rel.Assets, checksumsAsset)
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to download unverified binary", rel.TagName, checksumsAsset)
+	}
+
+	expected, err := fetchChecksum(ctx, sums.URL, asset.Name)
+	if err != nil {
+		return err
+	}
+
+	path, err := downloadVerified(ctx, asset, expected, output)
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.Text(lang, i18n.UpdateDownloaded, path, expected, checksumsAsset))
+	return nil
+}
+
+// fetchLatestRelease obtiene la descripción de la última versión
+func fetchLatestRelease(ctx context.Context) (*release, error) {
+	body, err := httpGet(ctx, latestReleaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query releases: %w", err)
+	}
+	defer body.Close()
+
+	var rel release
+	if err := json.NewDecoder(body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("invalid release data: %w", err)
+	}
+	return &rel, nil
+}
+
+// fetchChecksum busca el SHA-256 del archivo name en checksums.txt
+// (formato de sha256sum: "<hex>  <archivo>")
+func fetchChecksum(ctx context.Context, url, name string) (string, error) {
+	body, err := httpGet(ctx, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", checksumsAsset, err)
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", checksumsAsset, err)
+	}
+	return "", fmt.Errorf("%s has no entry for %s", checksumsAsset, name)
+}
+
+// downloadVerified descarga el archivo en dir y solo lo deja en su nombre
+// final si el SHA-256 coincide con expected
+func downloadVerified(ctx context.Context, asset releaseAsset, expected, dir string) (string, error) {
+	body, err := httpGet(ctx, asset.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(dir, "."+asset.Name+".*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if got := hex.EncodeToString(hash.Sum(nil)); got != expected {
+		return "", fmt.Errorf("%w for %s: got %s, expected %s", errChecksumMismatch, asset.Name, got, expected)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, asset.Name)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func httpGet(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ds205a-cli/"+ds205a.Version())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// platformAsset elige el binario para goos/goarch. El nombre se divide en
+// tokens por "_", "-" y "."; goos debe ir seguido inmediatamente de goarch
+// como tokens completos, para que "arm" no coincida con "…_linux_arm64"
+func platformAsset(assets []releaseAsset, goos, goarch string) (releaseAsset, bool) {
+	for _, a := range assets {
+		if a.Name == checksumsAsset {
+			continue
+		}
+		tokens := strings.FieldsFunc(strings.ToLower(a.Name), func(r rune) bool {
+			return r == '_' || r == '-' || r == '.'
+		})
+		for i := 0; i+1 < len(tokens); i++ {
+			if tokens[i] == goos && tokens[i+1] == goarch {
+				return a, true
+			}
+		}
+	}
+	return releaseAsset{}, false
+}
+
+func findAsset(assets []releaseAsset, name string) (releaseAsset, bool) {
+	for _, a := range assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+// compareVersions compara dos versiones vMAJOR.MINOR.PATCH (se ignoran los
+// sufijos de pre-release). ok es false si alguna no tiene ese formato
+func compareVersions(a, b string) (cmp int, ok bool) {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			if va[i] < vb[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestPlatformAsset(t *testing.T) {
+	assets := []releaseAsset{
+		{Name: "checksums.txt"},
+		{Name: "ds205a-cli_linux_arm64"},
+		{Name: "ds205a-cli_linux_amd64"},
+		{Name: "ds205a-cli_windows_amd64.exe"},
+		{Name: "ds205a-cli_linux_arm"},
+	}
+
+	tests := []struct {
+		goos, goarch string
+		want         string
+		found        bool
+	}{
+		{"linux", "arm", "ds205a-cli_linux_arm", true},
+		{"linux", "arm64", "ds205a-cli_linux_arm64", true},
+		{"linux", "amd64", "ds205a-cli_linux_amd64", true},
+		{"windows", "amd64", "ds205a-cli_windows_amd64.exe", true},
+		{"darwin", "arm64", "", false},
+		{"windows", "arm", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			got, ok := platformAsset(assets, tt.goos, tt.goarch)
+			if ok != tt.found || got.Name != tt.want {
+				t.Errorf("platformAsset = %q, %v; want %q, %v", got.Name, ok, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		cmp  int
+		ok   bool
+	}{
+		{"v1.2.3", "v1.2.3", 0, true},
+		{"v1.2.3", "v1.10.0", -1, true},
+		{"v2.0.0-rc1", "v1.9.9", 1, true},
+		{"dev", "v1.0.0", 0, false},
+	}
+
+	for _, tt := range tests {
+		cmp, ok := compareVersions(tt.a, tt.b)
+		if cmp != tt.cmp || ok != tt.ok {
+			t.Errorf("compareVersions(%q, %q) = %d, %v; want %d, %v", tt.a, tt.b, cmp, ok, tt.cmp, tt.ok)
+		}
+	}
+}
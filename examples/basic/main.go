@@ -37,7 +37,7 @@ func main() {
 		log.Printf("Warning: Could not get device info: %v", err)
 	} else {
 		fmt.Printf("Version: %d.%d.%d\n", deviceInfo.Version[0], deviceInfo.Version[1], deviceInfo.Version[2])
-		fmt.Printf("Machine Type: %d\n", deviceInfo.MachineType)
+		fmt.Printf("Machine Number: %d\n", deviceInfo.MachineNumber)
 	}
 
 	// Obtener estado inicial
@@ -67,10 +67,11 @@ func main() {
 
 	// Abrir paso izquierdo (entrada) con valor 1
 	fmt.Println("\nOpening left passage...")
-	if err := device.LeftOpen(ctx, 0x01); err != nil {
+	if result, err := device.LeftOpen(ctx, 0x01); err != nil {
 		log.Printf("Warning: Could not open left passage: %v", err)
 	} else {
-		fmt.Println("Left passage opened")
+		fmt.Printf("Left passage opened (gate status 0x%02X, %v, %d attempt(s))\n",
+			result.Status.GateStatus, result.Latency, result.Attempts)
 	}
 
 	// Esperar un poco
@@ -78,10 +79,11 @@ func main() {
 
 	// Abrir paso derecho (salida) con valor 1
 	fmt.Println("\nOpening right passage...")
-	if err := device.RightOpen(ctx, 0x01); err != nil {
+	if result, err := device.RightOpen(ctx, 0x01); err != nil {
 		log.Printf("Warning: Could not open right passage: %v", err)
 	} else {
-		fmt.Println("Right passage opened")
+		fmt.Printf("Right passage opened (gate status 0x%02X, %v, %d attempt(s))\n",
+			result.Status.GateStatus, result.Latency, result.Attempts)
 	}
 
 	// Esperar un poco
@@ -89,10 +91,11 @@ func main() {
 
 	// Cerrar la puerta
 	fmt.Println("\nClosing gate...")
-	if err := device.CloseGate(ctx); err != nil {
+	if result, err := device.CloseGate(ctx); err != nil {
 		log.Printf("Warning: Could not close gate: %v", err)
 	} else {
-		fmt.Println("Gate closed")
+		fmt.Printf("Gate closed (gate status 0x%02X, %v, %d attempt(s))\n",
+			result.Status.GateStatus, result.Latency, result.Attempts)
 	}
 
 	// Obtener estado final
@@ -2,7 +2,10 @@ module github.com/dumacp/ds205a
 
 go 1.25.0
 
-require go.bug.st/serial v1.6.2
+require (
+	go.bug.st/serial v1.6.2
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/creack/goselect v0.1.2 // indirect
@@ -0,0 +1,43 @@
+package device
+
+import (
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// Límites de lectura del puerto. Buses rápidos con varios equipos se
+// benefician de lecturas más grandes; puentes con poca memoria necesitan
+// lecturas y buffers pequeños
+const (
+	DefaultReadChunkSize = 32   // Bytes por llamada a Read del puerto
+	MinReadChunkSize     = 1    // Mínimo de bytes por lectura
+	MaxReadChunkSize     = 4096 // Máximo de bytes por lectura
+
+	DefaultMaxReadBuffer = 256                   // Bytes acumulados mientras se busca una trama
+	MinMaxReadBuffer     = protocol.ResponseSize // Debe caber al menos una respuesta completa
+	MaxMaxReadBuffer     = 65536                 // Tope del buffer de acumulación
+)
+
+// WithReadBuffer define el tamaño de cada lectura del puerto y el máximo de
+// bytes acumulados mientras se busca una trama. 0 conserva el valor
+// configurado; los valores fuera de rango hacen que New falle con el mismo
+// *ConfigError que reportaría Validate
+func WithReadBuffer(chunkSize, maxBuffer int) Option {
+	return func(d *Device) {
+		if chunkSize != 0 {
+			d.config.ReadChunkSize = chunkSize
+		}
+		if maxBuffer != 0 {
+			d.config.MaxReadBuffer = maxBuffer
+		}
+	}
+}
+
+// applyBufferDefaults completa los límites de lectura no configurados
+func applyBufferDefaults(config *Config) {
+	if config.ReadChunkSize <= 0 {
+		config.ReadChunkSize = DefaultReadChunkSize
+	}
+	if config.MaxReadBuffer <= 0 {
+		config.MaxReadBuffer = DefaultMaxReadBuffer
+	}
+}
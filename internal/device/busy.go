@@ -0,0 +1,136 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// ErrDeviceBusy indica que el dispositivo rechazó el comando por estar
+// ocupado (Command Execution = RespDeviceBusy, código no documentado),
+// típicamente porque hay un paso en curso
+var ErrDeviceBusy = errors.New("device busy")
+
+// BusyPolicy define qué hacer cuando el dispositivo responde ocupado
+type BusyPolicy int
+
+const (
+	BusyFailFast BusyPolicy = iota // Retornar ErrDeviceBusy de inmediato (default)
+	BusyRetry                      // Reenviar el comando cada intervalo hasta que lo acepte
+	BusyWaitIdle                   // Consultar el estado cada intervalo y reenviar con la puerta cerrada
+)
+
+// DefaultBusyPollInterval es el intervalo de espera por defecto entre
+// consultas mientras el dispositivo está ocupado
+const DefaultBusyPollInterval = 200 * time.Millisecond
+
+func (p BusyPolicy) String() string {
+	switch p {
+	case BusyFailFast:
+		return "FailFast"
+	case BusyRetry:
+		return "Retry"
+	case BusyWaitIdle:
+		return "WaitIdle"
+	default:
+		return fmt.Sprintf("BusyPolicy(%d)", int(p))
+	}
+}
+
+// WithBusyPolicy configura la respuesta a un rechazo por dispositivo
+// ocupado. Con BusyRetry y BusyWaitIdle se espera poll entre intentos
+// (DefaultBusyPollInterval si es <= 0), como máximo Config.Timeout o hasta
+// que termine el contexto; agotada la espera se retorna ErrDeviceBusy.
+//
+// El bus se libera durante cada espera y se vuelve a pedir para cada
+// consulta y reenvío, así que otros comandos (y, con WithTelemetryBudget,
+// los de control primero) pueden usarlo mientras tanto.
+//
+// Supuestos no cubiertos por la documentación del equipo: el rechazo por
+// ocupado se reconoce por Command Execution = 0x04 (RespDeviceBusy), y
+// BusyWaitIdle considera la puerta libre cuando GateState es
+// GateStateClosed, que en la tabla por defecto es solo GateStatus 0x00 (ver
+// WithGateStates)
+func WithBusyPolicy(policy BusyPolicy, poll time.Duration) Option {
+	return func(d *Device) {
+		if poll <= 0 {
+			poll = DefaultBusyPollInterval
+		}
+		d.busyPolicy = policy
+		d.busyPoll = poll
+	}
+}
+
+// transactBusy ejecuta la transacción aplicando la política de ocupado. El
+// bus debe estar reservado con hold; entre intentos se suelta y se vuelve a
+// reservar. Si retorna un error, el bus puede quedar sin reservar
+func (d *Device) transactBusy(ctx context.Context, hold *busHold, cmd protocol.CommandType, frame []byte) (*protocol.Response, error) {
+	response, err := d.transact(ctx, cmd, frame)
+	if d.busyPolicy == BusyFailFast || !errors.Is(err, ErrDeviceBusy) {
+		if errors.Is(err, ErrDeviceBusy) {
+			d.stats.failures.Add(1)
+		}
+		return response, err
+	}
+
+	deadline := d.clock.Now().Add(d.config.Timeout)
+	for errors.Is(err, ErrDeviceBusy) {
+		if !d.clock.Now().Before(deadline) {
+			d.stats.failures.Add(1)
+			return nil, fmt.Errorf("gave up after %v: %w", d.config.Timeout, err)
+		}
+
+		d.logger.Debug("Device busy, waiting", "command", cmd, "policy", d.busyPolicy, "poll", d.busyPoll)
+		hold.release()
+		if waitErr := d.sleep(ctx, d.busyPoll); waitErr != nil {
+			return nil, waitErr
+		}
+		if d.busyPolicy == BusyWaitIdle {
+			if waitErr := d.waitIdle(ctx, hold, deadline); waitErr != nil {
+				d.stats.failures.Add(1)
+				return nil, waitErr
+			}
+		}
+
+		if waitErr := hold.acquire(ctx); waitErr != nil {
+			return nil, waitErr
+		}
+		d.stats.retries.Add(1)
+		response, err = d.transact(ctx, cmd, frame)
+	}
+	return response, err
+}
+
+// waitIdle consulta el estado hasta que la puerta quede cerrada o se
+// alcance deadline. Reserva el bus con hold solo durante cada consulta
+func (d *Device) waitIdle(ctx context.Context, hold *busHold, deadline time.Time) error {
+	frame, err := protocol.BuildCommand(d.config.DeviceID, protocol.CmdGetStatus, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := hold.acquire(ctx); err != nil {
+			return err
+		}
+		d.stats.commands.Add(1)
+		response, err := d.transact(ctx, protocol.CmdGetStatus, frame)
+		hold.release()
+		if err != nil && !errors.Is(err, ErrDeviceBusy) {
+			return fmt.Errorf("failed to poll gate state: %w", err)
+		}
+		if err == nil && d.gateState(response.FaultEvent, response.GateStatus) == GateStateClosed {
+			return nil
+		}
+
+		if !d.clock.Now().Before(deadline) {
+			return fmt.Errorf("%w: gate not idle after %v", ErrDeviceBusy, d.config.Timeout)
+		}
+		if err := d.sleep(ctx, d.busyPoll); err != nil {
+			return err
+		}
+	}
+}
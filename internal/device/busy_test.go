@@ -0,0 +1,148 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// busyFrame es el rechazo por ocupado (Command Execution = 0x04)
+func busyFrame() []byte {
+	frame := responseFrame(0, 0)
+	frame[13] = byte(protocol.RespDeviceBusy)
+	setChecksum(frame)
+	return frame
+}
+
+// gateFrame es una respuesta exitosa con el GateStatus indicado
+func gateFrame(gate byte) []byte {
+	frame := responseFrame(0, 0)
+	frame[4] = gate
+	setChecksum(frame)
+	return frame
+}
+
+// writtenCommands retorna el código de comando de cada escritura
+func writtenCommands(port *rs485.ScriptedPort) []protocol.CommandType {
+	var cmds []protocol.CommandType
+	for _, w := range port.Writes() {
+		cmds = append(cmds, protocol.CommandType(w[3]))
+	}
+	return cmds
+}
+
+func TestBusyPolicy(t *testing.T) {
+	const poll = 200 * time.Millisecond
+	busy, open, closed := busyFrame(), gateFrame(0x01), gateFrame(0x00)
+	left, status := protocol.CmdLeftOpen, protocol.CmdGetStatus
+
+	tests := []struct {
+		name    string
+		policy  BusyPolicy
+		replies map[int][][]byte
+		wantErr error
+		want    []protocol.CommandType
+		sleeps  []time.Duration
+		busy    uint64
+	}{
+		{
+			name:    "fail fast",
+			policy:  BusyFailFast,
+			replies: map[int][][]byte{1: {busy}},
+			wantErr: ErrDeviceBusy,
+			want:    []protocol.CommandType{left},
+			busy:    1,
+		},
+		{
+			name:    "retry until accepted",
+			policy:  BusyRetry,
+			replies: map[int][][]byte{1: {busy}, 2: {busy}, 3: {closed}},
+			want:    []protocol.CommandType{left, left, left},
+			sleeps:  []time.Duration{poll, poll},
+			busy:    2,
+		},
+		{
+			name:    "wait for closed gate",
+			policy:  BusyWaitIdle,
+			replies: map[int][][]byte{1: {busy}, 2: {open}, 3: {closed}, 4: {closed}},
+			want:    []protocol.CommandType{left, status, status, left},
+			sleeps:  []time.Duration{poll, poll},
+			busy:    1,
+		},
+		{
+			name:    "retry gives up after timeout",
+			policy:  BusyRetry,
+			replies: map[int][][]byte{1: {busy}, 2: {busy}, 3: {busy}, 4: {busy}, 5: {busy}, 6: {busy}},
+			wantErr: ErrDeviceBusy,
+			want:    []protocol.CommandType{left, left, left, left, left, left},
+			sleeps:  []time.Duration{poll, poll, poll, poll, poll},
+			busy:    6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			port := replyOn(tt.replies)
+			d := newTestDevice(t, port, WithClock(clock), WithBusyPolicy(tt.policy, poll))
+
+			_, err := d.LeftOpen(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LeftOpen error = %v, want %v", err, tt.wantErr)
+			}
+			if got := writtenCommands(port); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commands = %v, want %v", got, tt.want)
+			}
+			if got := clock.Sleeps(); !reflect.DeepEqual(got, tt.sleeps) {
+				t.Errorf("waits = %v, want %v", got, tt.sleeps)
+			}
+			if got := d.Stats().Busy; got != tt.busy {
+				t.Errorf("Busy = %d, want %d", got, tt.busy)
+			}
+		})
+	}
+}
+
+func TestBusyWaitReleasesBus(t *testing.T) {
+	const poll = 200 * time.Millisecond
+	ctx := context.Background()
+	clock := newManualClock()
+	port := replyOn(map[int][][]byte{1: {busyFrame()}, 2: {gateFrame(0x00)}, 3: {gateFrame(0x00)}})
+	d := newTestDevice(t, port, WithClock(clock), WithBusyPolicy(BusyRetry, poll))
+
+	opened := make(chan error, 1)
+	go func() {
+		_, err := d.LeftOpen(ctx, 1)
+		opened <- err
+	}()
+	clock.waitTimers(t, 1) // LeftOpen espera con el dispositivo ocupado
+
+	// Mientras tanto el bus está libre para otros comandos
+	polled := make(chan error, 1)
+	go func() {
+		_, err := d.GetStatus(ctx)
+		polled <- err
+	}()
+	select {
+	case err := <-polled:
+		if err != nil {
+			t.Fatalf("GetStatus: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetStatus blocked while LeftOpen waited on a busy device")
+	}
+
+	clock.Advance(poll)
+	if err := <-opened; err != nil {
+		t.Fatalf("LeftOpen: %v", err)
+	}
+	want := []protocol.CommandType{protocol.CmdLeftOpen, protocol.CmdGetStatus, protocol.CmdLeftOpen}
+	if got := writtenCommands(port); !reflect.DeepEqual(got, want) {
+		t.Errorf("commands = %v, want %v", got, want)
+	}
+}
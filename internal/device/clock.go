@@ -0,0 +1,28 @@
+package device
+
+import (
+	"time"
+)
+
+// Clock abstrae el acceso al tiempo para que la lógica de reintentos y
+// esperas pueda probarse de forma determinista con un reloj falso
+type Clock interface {
+	Now() time.Time                         // Hora actual
+	After(d time.Duration) <-chan time.Time // Canal que recibe tras d
+}
+
+// systemClock implementa Clock usando el paquete time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SystemClock retorna el reloj basado en el tiempo real del sistema
+func SystemClock() Clock {
+	return systemClock{}
+}
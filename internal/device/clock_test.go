@@ -0,0 +1,114 @@
+package device
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// fakeClock es un reloj manual: cada espera se registra y avanza la hora
+// al instante, sin dormir
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// replyOn responde con las lecturas de replies según el número de escritura;
+// las escrituras sin entrada quedan sin respuesta
+func replyOn(replies map[int][][]byte) *rs485.ScriptedPort {
+	port := rs485.NewScriptedPort()
+	writes := 0
+	port.OnWrite = func([]byte) [][]byte {
+		writes++
+		return replies[writes]
+	}
+	return port
+}
+
+func TestRetryBackoff(t *testing.T) {
+	frame := responseFrame(5, 7)
+
+	tests := []struct {
+		name    string
+		replies map[int][][]byte
+		want    []time.Duration
+	}{
+		{
+			name:    "timeouts back off linearly",
+			replies: map[int][][]byte{3: {frame}},
+			want:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:    "collision adds jitter",
+			replies: map[int][][]byte{1: {frame[:10]}, 2: {frame}},
+			want:    []time.Duration{150 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			halfJitter := func(max time.Duration) time.Duration { return max / 2 }
+			d := newTestDevice(t, replyOn(tt.replies), WithClock(clock), WithJitter(halfJitter))
+			d.config.RetryCount = 3
+
+			if _, err := d.GetStatus(context.Background()); err != nil {
+				t.Fatalf("GetStatus: %v", err)
+			}
+			if got := clock.Sleeps(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("retry delays = %v, want %v", got, tt.want)
+			}
+			if got, want := d.Stats().Retries, uint64(len(tt.want)); got != want {
+				t.Errorf("Retries = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	clock := newFakeClock()
+	d := newTestDevice(t, replyOn(nil), WithClock(clock))
+	d.config.RetryCount = 2
+
+	if _, err := d.GetStatus(context.Background()); err == nil {
+		t.Fatal("GetStatus succeeded without a response")
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+	if got := clock.Sleeps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("retry delays = %v, want %v", got, want)
+	}
+	if stats := d.Stats(); stats.Timeouts != 3 || stats.Failures != 1 {
+		t.Errorf("Timeouts/Failures = %d/%d, want 3/1", stats.Timeouts, stats.Failures)
+	}
+}
@@ -0,0 +1,69 @@
+package device
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// readTimeoutKey es la clave de contexto para el timeout de lectura por llamada
+type readTimeoutKey struct{}
+
+// ContextWithReadTimeout retorna un contexto que reemplaza el timeout de
+// lectura configurado para los comandos ejecutados con él (por ejemplo, un
+// timeout largo para la primera respuesta después de un Reset)
+func ContextWithReadTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, readTimeoutKey{}, timeout)
+}
+
+// ReadTimeoutFromContext retorna el timeout de lectura del contexto, si existe
+func ReadTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(readTimeoutKey{}).(time.Duration)
+	if !ok || timeout <= 0 {
+		return 0, false
+	}
+	return timeout, true
+}
+
+// metadataKey es la clave de contexto para los metadatos del comando
+type metadataKey struct{}
+
+// Metadata contiene datos del origen de un comando (operador, transacción)
+// que se incluyen en los logs de auditoría del comando
+type Metadata struct {
+	OperatorID    string            // Identificador del operador
+	TransactionID string            // Identificador de la transacción (ej: venta de tiquete)
+	Labels        map[string]string // Datos adicionales
+}
+
+// ContextWithMetadata retorna un contexto que asocia los metadatos indicados
+// a los comandos ejecutados con él
+func ContextWithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+// MetadataFromContext retorna los metadatos del contexto, si existen
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	md, ok := ctx.Value(metadataKey{}).(Metadata)
+	return md, ok
+}
+
+// logArgs retorna los metadatos como pares clave/valor para el logger
+func (md Metadata) logArgs() []interface{} {
+	args := make([]interface{}, 0, 4+2*len(md.Labels))
+	if md.OperatorID != "" {
+		args = append(args, "operator", md.OperatorID)
+	}
+	if md.TransactionID != "" {
+		args = append(args, "transaction", md.TransactionID)
+	}
+	keys := make([]string, 0, len(md.Labels))
+	for k := range md.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, k, md.Labels[k])
+	}
+	return args
+}
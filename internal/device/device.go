@@ -1,8 +1,12 @@
 package device
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,15 +20,45 @@ var (
 	ErrInvalidResponse = errors.New("invalid response from device")
 	ErrCommunication   = errors.New("communication error")
 	ErrInvalidDeviceID = errors.New("invalid device ID")
+	ErrIncompleteFrame = errors.New("incomplete frame")
+	ErrNoResponse      = errors.New("no response")
 )
 
-// Device representa la implementación interna del dispositivo DS205A
+// Device representa la implementación interna del dispositivo DS205A.
+//
+// Es seguro para uso concurrente: cada transacción (trama de comando y su
+// respuesta, con reintentos) reserva el bus completo, de modo que consultas
+// de estado y comandos de control lanzados desde distintas goroutines nunca
+// intercalan tramas. Por defecto las transacciones en espera toman el bus en
+// el orden en que lo pidieron. Con WithTelemetryBudget los comandos de
+// control pasan delante de las consultas de estado, que esperan mientras
+// siga llegando control
 type Device struct {
 	mu     sync.RWMutex
+	seqMu  sync.Mutex    // Serializa las secuencias entre sí
+	bus    chan struct{} // Reserva del bus: una transacción (o RawConn) a la vez
 	conn   *rs485.Connection
 	config *Config
 	closed bool
 	logger Logger
+	clock  Clock
+	port   rs485.SerialPort // Puerto inyectado (opcional)
+	stats  stats
+
+	lockDir string // Directorio del bloqueo entre procesos (vacío: sin bloqueo)
+
+	jitter func(max time.Duration) time.Duration // Retardo aleatorio antes de reintentar tras una colisión
+
+	orientation Orientation        // Lado físico de la entrada
+	gateStates  map[byte]GateState // Tabla GateStatus → GateState (nil: la documentada)
+	state       *StateMachine      // Estado del torniquete visto por el controlador
+	gateOpened  bool               // La puerta se vio abierta desde el último comando (protegido por el bus)
+	changes     []stateChange      // Cambios de estado por notificar al liberar el bus (protegido por el bus)
+	quirks      quirkSet           // Particularidades de firmware observadas
+	tracer      *tracer            // Traza de transacciones (opcional)
+	busyPolicy  BusyPolicy         // Qué hacer si el dispositivo responde ocupado
+	busyPoll    time.Duration      // Espera entre intentos con el dispositivo ocupado
+	sched       scheduler          // Reparto del bus entre control y telemetría
 }
 
 // Config contiene la configuración del dispositivo DS205A
@@ -35,10 +69,13 @@ type Config struct {
 	StopBits     int           // Bits de parada (default: 1)
 	Parity       string        // Paridad: "none", "odd", "even" (default: "none")
 	Timeout      time.Duration // Timeout de operaciones (default: 5s)
-	ReadTimeout  time.Duration // Timeout de lectura (default: 2s)
-	WriteTimeout time.Duration // Timeout de escritura (default: 2s)
+	ReadTimeout  time.Duration // Timeout de lectura de una respuesta (default: adaptativo según baudrate)
+	WriteTimeout time.Duration // Timeout de escritura (default: adaptativo según baudrate)
 	DeviceID     byte          // ID del dispositivo (default: 0x01)
 	RetryCount   int           // Número de reintentos (default: 3)
+
+	ReadChunkSize int // Bytes por lectura del puerto (default: 32, rango 1-4096)
+	MaxReadBuffer int // Máximo de bytes acumulados buscando una trama (default: 256, rango 18-65536)
 }
 
 // LogLevel representa el nivel de logging
@@ -60,49 +97,119 @@ type Logger interface {
 	Error(msg string, args ...interface{})
 }
 
+// LogFormat representa el formato de salida del logger por defecto
+type LogFormat int
+
+const (
+	LogFormatText LogFormat = iota // Líneas de texto: [LEVEL] mensaje [args]
+	LogFormatJSON                  // Un objeto JSON por línea
+)
+
+// LoggerConfig configura el logger por defecto
+type LoggerConfig struct {
+	Level      LogLevel  // Nivel mínimo de logs
+	Output     io.Writer // Destino (default: os.Stdout)
+	Format     LogFormat // Formato de salida (default: texto)
+	Timestamps bool      // Agregar marca de tiempo a cada línea
+}
+
 // defaultLogger implementación básica de logger con niveles
 type defaultLogger struct {
-	level LogLevel
+	level      LogLevel
+	mu         sync.Mutex
+	out        io.Writer
+	format     LogFormat
+	timestamps bool
 }
 
 func (l *defaultLogger) Debug(msg string, args ...interface{}) {
 	if l.level >= LogLevelDebug {
-		fmt.Printf("[DEBUG] %s", msg)
-		if len(args) > 0 {
-			fmt.Printf(" %v", args)
-		}
-		fmt.Println()
+		l.write("DEBUG", msg, args)
 	}
 }
 
 func (l *defaultLogger) Info(msg string, args ...interface{}) {
 	if l.level >= LogLevelInfo {
-		fmt.Printf("[INFO] %s", msg)
-		if len(args) > 0 {
-			fmt.Printf(" %v", args)
-		}
-		fmt.Println()
+		l.write("INFO", msg, args)
 	}
 }
 
 func (l *defaultLogger) Warn(msg string, args ...interface{}) {
 	if l.level >= LogLevelWarn {
-		fmt.Printf("[WARN] %s", msg)
-		if len(args) > 0 {
-			fmt.Printf(" %v", args)
-		}
-		fmt.Println()
+		l.write("WARN", msg, args)
 	}
 }
 
 func (l *defaultLogger) Error(msg string, args ...interface{}) {
 	if l.level >= LogLevelError {
-		fmt.Printf("[ERROR] %s", msg)
-		if len(args) > 0 {
-			fmt.Printf(" %v", args)
+		l.write("ERROR", msg, args)
+	}
+}
+
+// write formatea y escribe una línea de log completa
+func (l *defaultLogger) write(level, msg string, args []interface{}) {
+	var line []byte
+	if l.format == LogFormatJSON {
+		line = l.formatJSON(level, msg, args)
+	} else {
+		line = l.formatText(level, msg, args)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := l.out
+	if out == nil {
+		out = os.Stdout
+	}
+	out.Write(line)
+}
+
+// formatText genera el formato de texto histórico: [LEVEL] mensaje [args]
+func (l *defaultLogger) formatText(level, msg string, args []interface{}) []byte {
+	var b strings.Builder
+	if l.timestamps {
+		b.WriteString(time.Now().Format(time.RFC3339Nano))
+		b.WriteByte(' ')
+	}
+	fmt.Fprintf(&b, "[%s] %s", level, msg)
+	if len(args) > 0 {
+		fmt.Fprintf(&b, " %v", args)
+	}
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+// formatJSON genera un objeto JSON por línea. Los argumentos se interpretan
+// como pares clave/valor; un argumento sin pareja se guarda como "extra"
+func (l *defaultLogger) formatJSON(level, msg string, args []interface{}) []byte {
+	entry := make(map[string]interface{}, len(args)/2+3)
+	if l.timestamps {
+		entry["time"] = time.Now().Format(time.RFC3339Nano)
+	}
+	entry["level"] = strings.ToLower(level)
+	entry["msg"] = strings.TrimSuffix(msg, ":")
+
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			entry["extra"] = fmt.Sprint(args[i])
+			break
 		}
-		fmt.Println()
+		key := fmt.Sprint(args[i])
+		value := args[i+1]
+		switch v := value.(type) {
+		case error:
+			value = v.Error()
+		case fmt.Stringer:
+			value = v.String()
+		}
+		entry[key] = value
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]string{"level": "error", "msg": "log encoding failed", "error": err.Error()})
 	}
+	return append(line, '\n')
 }
 
 // GetDefaultLogger retorna el logger por defecto (sin output)
@@ -115,6 +222,17 @@ func GetLoggerWithLevel(level LogLevel) Logger {
 	return &defaultLogger{level: level}
 }
 
+// NewLogger crea un logger por defecto con destino, formato y marcas de
+// tiempo configurables
+func NewLogger(config LoggerConfig) Logger {
+	return &defaultLogger{
+		level:      config.Level,
+		out:        config.Output,
+		format:     config.Format,
+		timestamps: config.Timestamps,
+	}
+}
+
 // Direction representa la dirección de paso
 type Direction int
 
@@ -134,19 +252,37 @@ const (
 
 // Status representa el estado del dispositivo según respuesta de 16 bytes
 type Status struct {
-	MachineNumber        uint8  // Número de máquina
-	VersionNumber        uint8  // Número de versión
-	FaultEvent           uint8  // Evento de falla
-	GateStatus           uint8  // Estado de la puerta
-	AlarmEvent           uint8  // Evento de alarma
-	InfraredStatus       uint8  // Estado infrarrojo
-	PowerSupplyVoltage   uint8  // Voltaje de alimentación
-	LeftPedestrianCount  uint32 // Contador de peatones izquierda (3 bytes convertidos a uint32)
-	RightPedestrianCount uint32 // Contador de peatones derecha (3 bytes convertidos a uint32)
+	MachineNumber        uint8     // Número de máquina
+	VersionNumber        uint8     // Número de versión
+	FaultEvent           uint8     // Evento de falla
+	GateStatus           uint8     // Estado de la puerta
+	GateState            GateState // Estado de la puerta interpretado (ver defaultGateStates)
+	AlarmEvent           uint8     // Evento de alarma
+	InfraredStatus       uint8     // Estado infrarrojo
+	PowerSupplyVoltage   uint8     // Voltaje de alimentación
+	LeftPedestrianCount  uint32    // Contador de peatones izquierda (3 bytes convertidos a uint32)
+	RightPedestrianCount uint32    // Contador de peatones derecha (3 bytes convertidos a uint32)
+	EntryCount           uint32    // Contador de entradas según la orientación de instalación
+	ExitCount            uint32    // Contador de salidas según la orientación de instalación
+
+	// ReceivedAt es el instante en que se completó la recepción de la trama
+	// (no el de su procesamiento). Incluye lectura monotónica, por lo que las
+	// diferencias entre estados son inmunes a ajustes del reloj de pared
+	ReceivedAt time.Time
+	Latency    time.Duration // Tiempo desde el envío del comando hasta la respuesta completa
+}
+
+// CommandResult es la evidencia de confirmación de un comando de control:
+// el estado que el dispositivo reportó en la misma respuesta, sin necesidad
+// de un GetStatus adicional
+type CommandResult struct {
+	Status   *Status       // Estado incluido en la respuesta al comando
+	Latency  time.Duration // Tiempo desde el envío hasta la respuesta completa
+	Attempts int           // Intentos usados (1 = sin reintentos)
 }
 
 // DeviceInfo contiene información del dispositivo
 type DeviceInfo struct {
-	Version     [3]uint8 // Versión del firmware [major, minor, patch]
-	MachineType uint8    // Tipo de máquina
+	Version       [3]uint8 // Versión del firmware [major, minor, patch]
+	MachineNumber uint8    // Número de máquina que respondió (el protocolo no reporta un tipo)
 }
@@ -0,0 +1,68 @@
+package device
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// exclusivePort detecta escrituras de un comando mientras la respuesta del
+// anterior sigue sin leerse, es decir, transacciones intercaladas
+type exclusivePort struct {
+	*rs485.ScriptedPort
+	interleaved atomic.Int32
+}
+
+func (p *exclusivePort) Write(b []byte) (int, error) {
+	if p.Pending() > 0 {
+		p.interleaved.Add(1)
+	}
+	return p.ScriptedPort.Write(b)
+}
+
+func TestConcurrentStatusAndControl(t *testing.T) {
+	sim := &simulator{}
+	port := &exclusivePort{ScriptedPort: rs485.NewScriptedPort()}
+	port.OnWrite = sim.respond
+	d := newTestDevice(t, port)
+
+	const pollers, polls, opens = 4, 25, 25
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < pollers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < polls; j++ {
+				if _, err := d.GetStatus(ctx); err != nil {
+					t.Errorf("GetStatus: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	var last uint32
+	for i := 0; i < opens; i++ {
+		result, err := d.LeftOpen(ctx, 1)
+		if err != nil {
+			t.Fatalf("LeftOpen: %v", err)
+		}
+		// Cada apertura recibe su propia respuesta, no la de una consulta
+		if got := result.Status.LeftPedestrianCount; got != last+1 {
+			t.Fatalf("LeftOpen %d confirmed count %d, want %d", i, got, last+1)
+		}
+		last = result.Status.LeftPedestrianCount
+	}
+	wg.Wait()
+
+	if n := port.interleaved.Load(); n != 0 {
+		t.Errorf("%d commands written before the previous response was read", n)
+	}
+	if n, want := len(port.Writes()), pollers*polls+opens; n != want {
+		t.Errorf("%d commands written, want %d", n, want)
+	}
+}
@@ -0,0 +1,150 @@
+package device
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// Particularidades de firmware observadas en las respuestas
+const (
+	QuirkNonStandardChecksum    = "rx-checksum-nonstandard"  // El checksum no sigue el algoritmo RX documentado
+	QuirkReservedBytesUsed      = "reserved-bytes-used"      // Los bytes sin documentar traen valores distintos de cero
+	QuirkUndocumentedGateStatus = "undocumented-gate-status" // GateStatus reporta valores fuera de la tabla documentada
+)
+
+// quirkNames mantiene el orden estable de las particularidades (bit i)
+var quirkNames = []string{
+	QuirkNonStandardChecksum,
+	QuirkReservedBytesUsed,
+	QuirkUndocumentedGateStatus,
+}
+
+// quirkSet acumula las particularidades observadas como máscara de bits,
+// junto con los valores de GateStatus no documentados (un bit por valor)
+type quirkSet struct {
+	mask  atomic.Uint32
+	gates [8]atomic.Uint32
+}
+
+func setBit(word *atomic.Uint32, bit uint32) {
+	for {
+		old := word.Load()
+		if old&bit != 0 || word.CompareAndSwap(old, old|bit) {
+			return
+		}
+	}
+}
+
+func (q *quirkSet) set(i int) {
+	setBit(&q.mask, uint32(1)<<i)
+}
+
+func (q *quirkSet) setGate(b byte) {
+	setBit(&q.gates[b/32], uint32(1)<<(b%32))
+}
+
+// reset olvida lo observado, al abrir de nuevo el dispositivo
+func (q *quirkSet) reset() {
+	q.mask.Store(0)
+	for i := range q.gates {
+		q.gates[i].Store(0)
+	}
+}
+
+func (q *quirkSet) names() []string {
+	mask := q.mask.Load()
+	var names []string
+	for i, name := range quirkNames {
+		if mask&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (q *quirkSet) gateValues() []byte {
+	var values []byte
+	for i := range q.gates {
+		word := q.gates[i].Load()
+		for j := 0; j < 32; j++ {
+			if word&(1<<j) != 0 {
+				values = append(values, byte(i*32+j))
+			}
+		}
+	}
+	return values
+}
+
+// observeQuirks registra las particularidades de una respuesta válida
+func (d *Device) observeQuirks(frame []byte, response *protocol.Response) {
+	if len(frame) >= protocol.ResponseSize && protocol.VerifyRx(frame) != nil {
+		d.quirks.set(0)
+	}
+	for _, b := range response.Reserved() {
+		if b != 0x00 {
+			d.quirks.set(1)
+			break
+		}
+	}
+	// Se compara con la tabla documentada y no con la configurada: valores
+	// registrados con WithGateStates siguen siendo particularidades
+	if _, ok := defaultGateStates[response.GateStatus]; !ok {
+		d.quirks.set(2)
+		d.quirks.setGate(response.GateStatus)
+	}
+}
+
+// Fingerprint identifica un equipo por su número de máquina y versión de
+// firmware, junto con las particularidades de protocolo observadas, para
+// inventariar flotas con firmwares mezclados. El protocolo no reporta un
+// tipo de máquina, por lo que la huella no lo incluye. Como el fabricante
+// solo documenta GateStatus 0x00, cualquier puerta abierta marca
+// QuirkUndocumentedGateStatus; los valores vistos en GateStatusValues son
+// los que distinguen un firmware de otro
+type Fingerprint struct {
+	MachineNumber    uint8    // Número de máquina
+	VersionNumber    uint8    // Versión de firmware reportada
+	Quirks           []string // Particularidades observadas desde la apertura, en orden estable
+	GateStatusValues []byte   // Valores de GateStatus no documentados observados, en orden
+	ID               string   // Resumen de MachineNumber y VersionNumber (16 dígitos hex)
+}
+
+func (f Fingerprint) String() string {
+	quirks := "none"
+	if len(f.Quirks) > 0 {
+		quirks = strings.Join(f.Quirks, ",")
+	}
+	return fmt.Sprintf("%s (machine %d, version %d, quirks %s)",
+		f.ID, f.MachineNumber, f.VersionNumber, quirks)
+}
+
+// Fingerprint consulta el estado del equipo y retorna su huella. Las
+// particularidades se acumulan con cada respuesta y no entran en ID, que
+// solo cambia si cambia el número de máquina o el firmware
+func (d *Device) Fingerprint(ctx context.Context) (*Fingerprint, error) {
+	info, err := d.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fingerprint: %w", err)
+	}
+
+	return &Fingerprint{
+		MachineNumber:    info.MachineNumber,
+		VersionNumber:    info.Version[0],
+		Quirks:           d.quirks.names(),
+		GateStatusValues: d.quirks.gateValues(),
+		ID:               fingerprintID(info.MachineNumber, info.Version[0]),
+	}, nil
+}
+
+// fingerprintID resume el número de máquina y la versión de firmware
+func fingerprintID(machine, version uint8) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d|%d", machine, version)
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
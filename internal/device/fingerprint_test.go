@@ -0,0 +1,127 @@
+package device
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+func TestFingerprintIDIgnoresQuirks(t *testing.T) {
+	plain := responseFrame(5, 7)
+	quirky := responseFrame(5, 7)
+	quirky[protocol.ReservedOffset] = 0x01
+	setChecksum(quirky)
+
+	d := newTestDevice(t, rs485.NewScriptedPort(plain, quirky, plain))
+
+	before, err := d.Fingerprint(context.Background())
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if _, err := d.GetStatus(context.Background()); err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	after, err := d.Fingerprint(context.Background())
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+
+	if want := []string{QuirkReservedBytesUsed}; !reflect.DeepEqual(after.Quirks, want) {
+		t.Errorf("Quirks = %v, want %v", after.Quirks, want)
+	}
+	if before.ID != after.ID {
+		t.Errorf("ID changed from %s to %s after a quirk was observed", before.ID, after.ID)
+	}
+	if after.MachineNumber != testDeviceID {
+		t.Errorf("MachineNumber = %d, want %d", after.MachineNumber, testDeviceID)
+	}
+}
+
+func TestFingerprintGateQuirkUsesDocumentedTable(t *testing.T) {
+	ctx := context.Background()
+	// 0x10 registrado como reposo sigue siendo un valor no documentado
+	d := newTestDevice(t, gateReplies(0x00, 0x10, 0x01, 0x10, 0x00),
+		WithGateStates(map[byte]GateState{0x10: GateStateClosed}))
+
+	fp, err := d.Fingerprint(ctx)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if len(fp.Quirks) != 0 || len(fp.GateStatusValues) != 0 {
+		t.Errorf("documented gate status: Quirks = %v, GateStatusValues = %v, want none", fp.Quirks, fp.GateStatusValues)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := d.GetStatus(ctx); err != nil {
+			t.Fatalf("GetStatus %d: %v", i, err)
+		}
+	}
+	fp, err = d.Fingerprint(ctx)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if want := []string{QuirkUndocumentedGateStatus}; !reflect.DeepEqual(fp.Quirks, want) {
+		t.Errorf("Quirks = %v, want %v", fp.Quirks, want)
+	}
+	if want := []byte{0x01, 0x10}; !bytes.Equal(fp.GateStatusValues, want) {
+		t.Errorf("GateStatusValues = % X, want % X", fp.GateStatusValues, want)
+	}
+}
+
+func TestFingerprintQuirksResetOnOpen(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDevice(t, gateReplies(0x01, 0x00))
+
+	if _, err := d.GetStatus(ctx); err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if got := d.Stats().Quirks; len(got) == 0 {
+		t.Fatalf("Stats().Quirks empty after an undocumented gate status")
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := d.Open(); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	fp, err := d.Fingerprint(ctx)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if len(fp.Quirks) != 0 || len(fp.GateStatusValues) != 0 {
+		t.Errorf("after reopening Quirks = %v, GateStatusValues = %v, want none", fp.Quirks, fp.GateStatusValues)
+	}
+}
+
+func TestFingerprintInStatsAndEvents(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDevice(t, gateReplies(0x00, 0x00, 0x00))
+
+	if got := d.Stats().Fingerprint; got != "" {
+		t.Errorf("Stats().Fingerprint = %q before any response, want empty", got)
+	}
+
+	var events []TransitionEvent
+	d.StateMachine().OnTransitionEvent(func(e TransitionEvent) { events = append(events, e) })
+
+	fp, err := d.Fingerprint(ctx)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if _, err := d.LeftOpen(ctx, 1); err != nil {
+		t.Fatalf("LeftOpen: %v", err)
+	}
+
+	if got := d.Stats().Fingerprint; got != fp.ID {
+		t.Errorf("Stats().Fingerprint = %q, want %q", got, fp.ID)
+	}
+	want := []TransitionEvent{{From: StateIdle, To: StateGranted, Fingerprint: fp.ID}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+}
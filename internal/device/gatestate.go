@@ -0,0 +1,77 @@
+package device
+
+import (
+	"fmt"
+)
+
+// GateState es el estado de la puerta derivado del byte GateStatus.
+// Solo incluye los estados que el protocolo documentado permite distinguir:
+// el fabricante no publica los códigos de apertura, así que una puerta
+// abierta se reporta como GateStateUnknown
+type GateState int
+
+const (
+	GateStateUnknown GateState = iota // Valor de GateStatus sin mapeo conocido
+	GateStateClosed                   // Puerta cerrada, en reposo
+	GateStateFault                    // Falla reportada por el equipo
+)
+
+func (s GateState) String() string {
+	switch s {
+	case GateStateUnknown:
+		return "unknown"
+	case GateStateClosed:
+		return "closed"
+	case GateStateFault:
+		return "fault"
+	default:
+		return fmt.Sprintf("GateState(%d)", int(s))
+	}
+}
+
+// defaultGateStates es la tabla de valores de GateStatus documentados por
+// el fabricante (doc/reponse.csv):
+//
+//	GateStatus | Estado
+//	-----------+-----------------
+//	0x00       | GateStateClosed
+//
+// El resto de valores no está documentado y se reporta como
+// GateStateUnknown; WithGateStates permite registrar los observados en
+// cada firmware. Un FaultEvent distinto de 0x00 tiene prioridad y da
+// GateStateFault
+var defaultGateStates = map[byte]GateState{
+	0x00: GateStateClosed,
+}
+
+// WithGateStates agrega (o reemplaza) entradas de la tabla GateStatus →
+// GateState, para firmwares que reportan valores no documentados (por
+// ejemplo, otro código de reposo que debe tratarse como GateStateClosed)
+func WithGateStates(states map[byte]GateState) Option {
+	return func(d *Device) {
+		if d.gateStates == nil {
+			d.gateStates = make(map[byte]GateState, len(defaultGateStates)+len(states))
+			for b, s := range defaultGateStates {
+				d.gateStates[b] = s
+			}
+		}
+		for b, s := range states {
+			d.gateStates[b] = s
+		}
+	}
+}
+
+// gateState deriva el estado de la puerta de los bytes de la respuesta
+func (d *Device) gateState(faultEvent, gateStatus byte) GateState {
+	if faultEvent != 0x00 {
+		return GateStateFault
+	}
+	table := d.gateStates
+	if table == nil {
+		table = defaultGateStates
+	}
+	if s, ok := table[gateStatus]; ok {
+		return s
+	}
+	return GateStateUnknown
+}
@@ -2,7 +2,9 @@ package device
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/dumacp/ds205a/internal/protocol"
@@ -10,31 +12,46 @@ import (
 )
 
 // New crea una nueva instancia del dispositivo DS205A
-func New(config *Config) (*Device, error) {
-	// Validar configuración
-	if err := validateConfig(config); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+func New(config *Config, opts ...Option) (*Device, error) {
+	// Validar configuración (reporta todos los problemas a la vez)
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
+	// Copiar la configuración para completar los valores por defecto
+	cfg := *config
+	applyTimingDefaults(&cfg)
+	applyBufferDefaults(&cfg)
+
 	device := &Device{
-		config: config,
+		config: &cfg,
 		closed: true,
 		logger: GetDefaultLogger(),
+		clock:  SystemClock(),
+		jitter: randomJitter,
+		bus:    make(chan struct{}, 1),
+		state:  NewStateMachine(),
+
+		busyPoll: DefaultBusyPollInterval,
 	}
 
-	return device, nil
-}
+	for _, opt := range opts {
+		opt(device)
+	}
 
-// NewWithLogger crea una nueva instancia con logger personalizado
-func NewWithLogger(config *Config, logger Logger) (*Device, error) {
-	device, err := New(config)
-	if err != nil {
+	// Las opciones pueden cambiar campos de la configuración
+	if err := device.config.Validate(); err != nil {
 		return nil, err
 	}
-	device.logger = logger
+
 	return device, nil
 }
 
+// NewWithLogger crea una nueva instancia con logger personalizado
+func NewWithLogger(config *Config, logger Logger, opts ...Option) (*Device, error) {
+	return New(config, append([]Option{WithLogger(logger)}, opts...)...)
+}
+
 // Open abre la conexión con el dispositivo
 func (d *Device) Open() error {
 	d.mu.Lock()
@@ -45,7 +62,7 @@ func (d *Device) Open() error {
 	}
 
 	// Crear conexión RS485
-	conn, err := rs485.NewConnection(&rs485.Config{
+	connConfig := &rs485.Config{
 		Port:         d.config.Port,
 		BaudRate:     d.config.BaudRate,
 		DataBits:     d.config.DataBits,
@@ -53,7 +70,16 @@ func (d *Device) Open() error {
 		Parity:       d.config.Parity,
 		ReadTimeout:  d.config.ReadTimeout,
 		WriteTimeout: d.config.WriteTimeout,
-	})
+		LockDir:      d.lockDir,
+	}
+
+	var conn *rs485.Connection
+	var err error
+	if d.port != nil {
+		conn, err = rs485.NewConnectionWithPort(connConfig, d.port)
+	} else {
+		conn, err = rs485.NewConnection(connConfig)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to open RS485 connection: %w", err)
 	}
@@ -64,6 +90,7 @@ func (d *Device) Open() error {
 
 	d.conn = conn
 	d.closed = false
+	d.quirks.reset()
 
 	d.logger.Info("Device opened successfully", "port", d.config.Port)
 	return nil
@@ -97,8 +124,19 @@ func (d *Device) IsOpen() bool {
 	return !d.closed && d.conn != nil
 }
 
-// Write envía datos al dispositivo
+// Write envía datos al dispositivo fuera de una transacción. Si el bus está
+// reservado (comando en curso o RawConn abierta) retorna ErrConcurrentAccess
+// en lugar de intercalar bytes en la trama de otro
 func (d *Device) Write(data []byte) error {
+	if !d.tryAcquireBus() {
+		return ErrConcurrentAccess
+	}
+	defer d.releaseBus()
+	return d.write(data)
+}
+
+// write envía datos al dispositivo. El bus debe estar reservado
+func (d *Device) write(data []byte) error {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
@@ -116,34 +154,78 @@ func (d *Device) Write(data []byte) error {
 	return nil
 }
 
-// Read lee datos del dispositivo manejando fragmentación de tramas
+// flush descarta los bytes pendientes de lectura en la conexión
+func (d *Device) flush() {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed || d.conn == nil {
+		return
+	}
+	if err := d.conn.Flush(); err != nil {
+		d.logger.Debug("Failed to flush input buffer", "error", err)
+	}
+}
+
+// Read lee una trama del dispositivo fuera de una transacción, manejando
+// fragmentación. Si el bus está reservado retorna ErrConcurrentAccess en
+// lugar de consumir la respuesta de otro comando
 func (d *Device) Read(ctx context.Context, buffer []byte) (int, error) {
+	if !d.tryAcquireBus() {
+		return 0, ErrConcurrentAccess
+	}
+	defer d.releaseBus()
+	n, _, err := d.readFrame(ctx, buffer)
+	return n, err
+}
+
+// readFrame lee una trama de respuesta y retorna también el instante en que
+// se completó su recepción. El bus debe estar reservado
+func (d *Device) readFrame(ctx context.Context, buffer []byte) (int, time.Time, error) {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
 	if d.closed || d.conn == nil {
-		return 0, ErrDeviceNotOpen
+		return 0, time.Time{}, ErrDeviceNotOpen
 	}
 
 	// Buffer para acumular datos
 	var accumulated []byte
-	tempBuffer := make([]byte, 32) // Leer chunks más grandes
+	tempBuffer := make([]byte, d.config.ReadChunkSize)
+
+	// Aplicar el timeout de lectura por llamada, si el contexto lo define
+	readTimeout := d.config.ReadTimeout
+	if timeout, ok := ReadTimeoutFromContext(ctx); ok && timeout != readTimeout {
+		readTimeout = timeout
+		if err := d.conn.SetReadTimeout(readTimeout); err != nil {
+			return 0, time.Time{}, fmt.Errorf("failed to set read timeout: %w", err)
+		}
+		defer d.conn.SetReadTimeout(d.config.ReadTimeout)
+	}
 
-	// Leer datos hasta encontrar trama completa o timeout
-	maxReadAttempts := 30
+	// Leer datos hasta encontrar trama completa o timeout. El deadline es el
+	// límite real; el de lecturas solo evita un lazo sin fin si el reloj no
+	// avanza, y alcanza para recorrer MaxReadBuffer bytes de ruido más una
+	// trama aun con ReadChunkSize pequeño
+	maxReadAttempts := max(30, (d.config.MaxReadBuffer+protocol.ResponseSize)/d.config.ReadChunkSize+1)
+	deadline := d.clock.Now().Add(readTimeout)
 
 	initialByte := false
 
 	for attempt := 0; attempt < maxReadAttempts; attempt++ {
 		select {
 		case <-ctx.Done():
-			return 0, ctx.Err()
+			return 0, time.Time{}, ctx.Err()
 		default:
 		}
+		if attempt > 0 && !d.clock.Now().Before(deadline) {
+			break
+		}
 		n, err := d.conn.Read(tempBuffer)
+		receivedAt := d.clock.Now()
 		if err != nil {
 			if n <= 0 && len(accumulated) == 0 {
-				return len(accumulated), err
+				return len(accumulated), time.Time{}, err
 			}
 		}
 
@@ -171,11 +253,31 @@ func (d *Device) Read(ctx context.Context, buffer []byte) (int, error) {
 				}
 			}
 
-			// Verificar si tenemos la trama completa
+			// Sin header, todo lo acumulado es ruido: limitar la acumulación
+			// descartando los bytes más antiguos
+			if excess := len(accumulated) - d.config.MaxReadBuffer; !initialByte && excess > 0 {
+				d.logger.Debug("Read buffer full, discarding oldest bytes:", "count", excess)
+				accumulated = accumulated[excess:]
+			}
+
+			// Un 0x7F en ruido o en restos de una trama anterior se confunde
+			// con el header: si la candidata no es de este dispositivo y más
+			// adelante hay un header mejor, resincronizar a él
+			if initialByte && len(accumulated) >= protocol.ResponseSize {
+				if next := nextHeader(accumulated, d.config.DeviceID); next > 0 {
+					d.logger.Debug("Resyncing to next header:", "discarded", next)
+					accumulated = accumulated[next:]
+				}
+			}
+
+			// Verificar si tenemos la trama completa. El header puede llegar
+			// solo al final de una ráfaga y el resto en ráfagas posteriores
+			// (algunos adaptadores USB cortan ahí): se sigue acumulando hasta
+			// completar ResponseSize bytes desde el header
 			if initialByte && len(accumulated) >= protocol.ResponseSize {
 				copy(buffer, accumulated[:protocol.ResponseSize])
 				d.logger.Debug("Complete frame received:", "data", fmt.Sprintf("[% 02X]", buffer[:protocol.ResponseSize]))
-				return protocol.ResponseSize, nil
+				return protocol.ResponseSize, receivedAt, nil
 			}
 		}
 	}
@@ -184,11 +286,27 @@ func (d *Device) Read(ctx context.Context, buffer []byte) (int, error) {
 	if len(accumulated) > 0 {
 		copy(buffer, accumulated)
 		d.logger.Debug("Timeout with incomplete frame:", "received", len(accumulated), "expected", protocol.ResponseSize)
-		return len(accumulated), fmt.Errorf("timeout: incomplete frame received %d bytes, expected %d", len(accumulated), protocol.ResponseSize)
+		return len(accumulated), time.Time{}, fmt.Errorf("timeout: %w: received %d bytes, expected %d", ErrIncompleteFrame, len(accumulated), protocol.ResponseSize)
 	}
 
 	d.logger.Debug("No data received")
-	return 0, fmt.Errorf("timeout: no data received")
+	return 0, time.Time{}, fmt.Errorf("timeout: %w", ErrNoResponse)
+}
+
+// nextHeader retorna la posición del siguiente header candidato en frame
+// cuando el de la posición 0 trae otro número de máquina, o 0 si no hay uno
+// mejor. Un candidato cuyo número de máquina aún no llega también sirve: se
+// prefiere esperar al resto antes que aceptar una trama ajena
+func nextHeader(frame []byte, deviceID byte) int {
+	if frame[2] == deviceID {
+		return 0
+	}
+	for i := 1; i < len(frame); i++ {
+		if frame[i] == protocol.ResponseHeader && (i+2 >= len(frame) || frame[i+2] == deviceID) {
+			return i
+		}
+	}
+	return 0
 }
 
 // SendCommand envía un comando y espera respuesta
@@ -203,53 +321,167 @@ func (d *Device) SendCommand(ctx context.Context, cmd protocol.CommandType, data
 		return nil, fmt.Errorf("failed to build command: %w", err)
 	}
 
+	// Reservar el bus durante la transacción (incluidos los reintentos). Los
+	// cambios de estado se notifican después de liberarlo
+	hold := &busHold{d: d, class: classOf(cmd)}
+	defer func() {
+		hold.release()
+		notifyChanges(hold.changes)
+	}()
+	if err := hold.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	start := d.clock.Now()
+	d.stats.commands.Add(1)
+	response, err := d.transactBusy(ctx, hold, cmd, frame)
+	hold.release()
+
+	md, hasMetadata := MetadataFromContext(ctx)
+	if d.tracer != nil {
+		d.tracer.trace(start, d.clock.Now().Sub(start), cmd, response, hold.retries, md, err)
+	}
+
+	// Registrar el comando con los metadatos de origen para auditoría
+	if hasMetadata {
+		args := append([]interface{}{"command", cmd}, md.logArgs()...)
+		if err != nil {
+			d.logger.Warn("Command failed", append(args, "error", err)...)
+		} else {
+			d.logger.Info("Command executed", args...)
+		}
+	}
+
+	return response, err
+}
+
+// transact ejecuta el intercambio comando/respuesta con reintentos. El bus
+// debe estar reservado
+func (d *Device) transact(ctx context.Context, cmd protocol.CommandType, frame []byte) (*protocol.Response, error) {
 	// Enviar comando con reintentos
-	var response *protocol.Response
+	var lastErr error
+	collision := false
 	for attempt := 0; attempt <= d.config.RetryCount; attempt++ {
 		if attempt > 0 {
-			d.logger.Debug("Retrying command", "attempt", attempt, "command", cmd)
-			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+			delay := time.Duration(attempt) * 100 * time.Millisecond
+			if collision {
+				// Esperar un tiempo aleatorio para no volver a colisionar con
+				// el otro transmisor
+				delay += d.jitter(time.Duration(attempt) * 100 * time.Millisecond)
+			}
+			d.logger.Debug("Retrying command", "attempt", attempt, "command", cmd, "delay", delay)
+			yield, err := d.retryWait(ctx, delay)
+			if err != nil {
+				return nil, err
+			}
+			if yield {
+				// Ceder el bus a un comando de control en espera
+				d.sched.preemptions.Add(1)
+				d.stats.failures.Add(1)
+				return nil, fmt.Errorf("%w: %w", ErrPreempted, lastErr)
+			}
+			d.stats.retries.Add(1)
+			if collision {
+				// Descartar los restos de la trama corrupta
+				d.flush()
+			}
 		}
+		collision = false
 
 		// Escribir comando
-		if err := d.Write(frame); err != nil {
+		sentAt := d.clock.Now()
+		if err := d.write(frame); err != nil {
 			d.logger.Warn("Failed to write command", "error", err)
-			if attempt == d.config.RetryCount {
-				return nil, fmt.Errorf("failed to send command after %d attempts: %w",
-					d.config.RetryCount+1, err)
-			}
+			lastErr = fmt.Errorf("failed to send command: %w", err)
 			continue
 		}
 
 		// Leer respuesta
 		responseBuffer := make([]byte, protocol.ResponseSize)
-		n, err := d.Read(ctx, responseBuffer)
+		n, receivedAt, err := d.readFrame(ctx, responseBuffer)
 		if err != nil {
-			if attempt == d.config.RetryCount {
-				return nil, fmt.Errorf("failed to read response after %d attempts: %w",
-					d.config.RetryCount+1, err)
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			switch {
+			case errors.Is(err, ErrIncompleteFrame):
+				collision = true
+			case errors.Is(err, ErrNoResponse):
+				d.stats.timeouts.Add(1)
+			}
+			lastErr = fmt.Errorf("failed to read response: %w", err)
+			if collision {
+				d.stats.collisions.Add(1)
+				d.logger.Warn("Possible bus collision", "command", cmd, "error", err)
 			}
 			continue
 		}
 
 		// Parsear respuesta con validación de Machine ID
-		response, err = protocol.ParseResponse(responseBuffer[:n], d.config.DeviceID)
+		response, err := protocol.ParseResponse(responseBuffer[:n], d.config.DeviceID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse response after %d attempts: %w",
-				d.config.RetryCount+1, err)
+			lastErr = fmt.Errorf("failed to parse response: %w", err)
+			if isFramingError(err) {
+				collision = true
+				d.stats.collisions.Add(1)
+				d.logger.Warn("Possible bus collision", "command", cmd, "error", err)
+				continue
+			}
+			// El dispositivo respondió correctamente pero rechazó el comando.
+			// Un rechazo por ocupado lo resuelve la política de ocupado. El
+			// código 0x04 se asume: la documentación no lo describe
+			if responseBuffer[13] == byte(protocol.RespDeviceBusy) {
+				d.stats.busy.Add(1)
+				return nil, fmt.Errorf("%w: %w", ErrDeviceBusy, lastErr)
+			}
+			d.stats.failures.Add(1)
+			return nil, lastErr
 		}
 
-		// Comando exitoso
-		break
+		// Comando exitoso (la validación del código de respuesta se hace en ParseResponse)
+		response.ReceivedAt = receivedAt
+		response.Latency = receivedAt.Sub(sentAt)
+		response.Attempts = attempt + 1
+		if d.stats.restarts.observe(cmd, response) {
+			d.logger.Warn("Device restart detected", "command", cmd,
+				"version", response.VersionNumber,
+				"left", response.GetLeftCount(), "right", response.GetRightCount())
+		}
+		d.observeQuirks(responseBuffer[:n], response)
+		d.trackState(cmd, response)
+		return response, nil
 	}
 
-	if response == nil {
-		return nil, fmt.Errorf("failed to get valid response after %d attempts",
-			d.config.RetryCount+1)
+	d.stats.failures.Add(1)
+	return nil, fmt.Errorf("failed to get valid response after %d attempts: %w",
+		d.config.RetryCount+1, lastErr)
+}
+
+// isFramingError indica si el error de parseo corresponde a una trama
+// corrupta o ajena (típico de dos transmisores hablando a la vez)
+func isFramingError(err error) bool {
+	return errors.Is(err, protocol.ErrFrameTooShort) ||
+		errors.Is(err, protocol.ErrInvalidHeader) ||
+		errors.Is(err, protocol.ErrMachineIDMismatch)
+}
+
+// randomJitter retorna un retardo aleatorio en [0, max)
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
 	}
+	return time.Duration(rand.Int64N(int64(max)))
+}
 
-	// La validación del código de respuesta ya se hace en ParseResponse
-	return response, nil
+// sleep espera la duración indicada usando el reloj del dispositivo,
+// abortando si el contexto se cancela
+func (d *Device) sleep(ctx context.Context, delay time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-d.clock.After(delay):
+		return nil
+	}
 }
 
 // GetConfig retorna una copia de la configuración actual
@@ -260,35 +492,6 @@ func (d *Device) GetConfig() *Config {
 	return &configCopy
 }
 
-// validateConfig valida la configuración del dispositivo
-func validateConfig(config *Config) error {
-	if config.Port == "" {
-		return fmt.Errorf("port cannot be empty")
-	}
-
-	if config.BaudRate <= 0 {
-		return fmt.Errorf("baud rate must be positive")
-	}
-
-	if config.DataBits < 5 || config.DataBits > 8 {
-		return fmt.Errorf("data bits must be between 5 and 8")
-	}
-
-	if config.StopBits < 1 || config.StopBits > 2 {
-		return fmt.Errorf("stop bits must be 1 or 2")
-	}
-
-	if config.Parity != "none" && config.Parity != "odd" && config.Parity != "even" {
-		return fmt.Errorf("parity must be 'none', 'odd', or 'even'")
-	}
-
-	if config.Timeout <= 0 {
-		return fmt.Errorf("timeout must be positive")
-	}
-
-	return nil
-}
-
 // GetStatus obtiene el estado actual del dispositivo
 func (d *Device) GetStatus(ctx context.Context) (*Status, error) {
 	response, err := d.SendCommand(ctx, protocol.CmdGetStatus, nil)
@@ -296,6 +499,12 @@ func (d *Device) GetStatus(ctx context.Context) (*Status, error) {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
 
+	return d.statusFromResponse(response), nil
+}
+
+// statusFromResponse construye el estado a partir de una trama de respuesta.
+// Todas las respuestas del DS205A incluyen el estado completo del equipo
+func (d *Device) statusFromResponse(response *protocol.Response) *Status {
 	// Convertir contadores de bytes a uint32
 	leftCount := uint32(response.LeftPedestrianCount[0])<<16 |
 		uint32(response.LeftPedestrianCount[1])<<8 |
@@ -310,23 +519,36 @@ func (d *Device) GetStatus(ctx context.Context) (*Status, error) {
 		VersionNumber:        response.VersionNumber,
 		FaultEvent:           response.FaultEvent,
 		GateStatus:           response.GateStatus,
+		GateState:            d.gateState(response.FaultEvent, response.GateStatus),
 		AlarmEvent:           response.AlarmEvent,
 		InfraredStatus:       response.InfraredStatus,
 		PowerSupplyVoltage:   response.PowerSupplyVoltage,
 		LeftPedestrianCount:  leftCount,
 		RightPedestrianCount: rightCount,
+		ReceivedAt:           response.ReceivedAt,
+		Latency:              response.Latency,
 	}
+	d.applyOrientation(status)
 
-	return status, nil
+	return status
+}
+
+// commandResult construye el resultado de un comando de control
+func (d *Device) commandResult(response *protocol.Response) *CommandResult {
+	return &CommandResult{
+		Status:   d.statusFromResponse(response),
+		Latency:  response.Latency,
+		Attempts: response.Attempts,
+	}
 }
 
 // LeftOpen abre el paso por la izquierda
-func (d *Device) LeftOpen(ctx context.Context, value uint8) error {
-	_, err := d.SendCommand(ctx, protocol.CmdLeftOpen, []byte{value})
+func (d *Device) LeftOpen(ctx context.Context, value uint8) (*CommandResult, error) {
+	response, err := d.SendCommand(ctx, protocol.CmdLeftOpen, []byte{value})
 	if err != nil {
-		return fmt.Errorf("failed to open left passage: %w", err)
+		return nil, fmt.Errorf("failed to open left passage: %w", err)
 	}
-	return nil
+	return d.commandResult(response), nil
 }
 
 // LeftAlwaysOpen mantiene siempre abierto el paso izquierdo
@@ -339,12 +561,12 @@ func (d *Device) LeftAlwaysOpen(ctx context.Context) error {
 }
 
 // RightOpen abre el paso por la derecha
-func (d *Device) RightOpen(ctx context.Context, value uint8) error {
-	_, err := d.SendCommand(ctx, protocol.CmdRightOpen, []byte{value})
+func (d *Device) RightOpen(ctx context.Context, value uint8) (*CommandResult, error) {
+	response, err := d.SendCommand(ctx, protocol.CmdRightOpen, []byte{value})
 	if err != nil {
-		return fmt.Errorf("failed to open right passage: %w", err)
+		return nil, fmt.Errorf("failed to open right passage: %w", err)
 	}
-	return nil
+	return d.commandResult(response), nil
 }
 
 // RightAlwaysOpen mantiene siempre abierto el paso derecho
@@ -357,12 +579,12 @@ func (d *Device) RightAlwaysOpen(ctx context.Context) error {
 }
 
 // CloseGate cierra la puerta/torniquete
-func (d *Device) CloseGate(ctx context.Context) error {
-	_, err := d.SendCommand(ctx, protocol.CmdCloseGate, nil)
+func (d *Device) CloseGate(ctx context.Context) (*CommandResult, error) {
+	response, err := d.SendCommand(ctx, protocol.CmdCloseGate, nil)
 	if err != nil {
-		return fmt.Errorf("failed to close gate: %w", err)
+		return nil, fmt.Errorf("failed to close gate: %w", err)
 	}
-	return nil
+	return d.commandResult(response), nil
 }
 
 // ForbiddenLeftPassage prohíbe el paso por la izquierda
@@ -419,8 +641,8 @@ func (d *Device) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
 	}
 
 	info := &DeviceInfo{
-		Version:     [3]uint8{response.VersionNumber, 0, 0}, // Usar VersionNumber de la respuesta
-		MachineType: response.MachineNumber,                 // Usar el número de máquina como tipo
+		Version:       [3]uint8{response.VersionNumber, 0, 0}, // Usar VersionNumber de la respuesta
+		MachineNumber: response.MachineNumber,
 	}
 
 	return info, nil
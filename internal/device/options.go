@@ -0,0 +1,60 @@
+package device
+
+import (
+	"time"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// Option configura aspectos opcionales del dispositivo
+type Option func(*Device)
+
+// WithClock reemplaza el reloj usado para reintentos y esperas
+func WithClock(clock Clock) Option {
+	return func(d *Device) {
+		if clock != nil {
+			d.clock = clock
+		}
+	}
+}
+
+// WithJitter reemplaza la fuente del retardo aleatorio que se suma antes
+// de reintentar tras una colisión. fn debe retornar un valor en [0, max).
+// Junto con WithClock permite probar los reintentos de forma determinista
+func WithJitter(fn func(max time.Duration) time.Duration) Option {
+	return func(d *Device) {
+		if fn != nil {
+			d.jitter = fn
+		}
+	}
+}
+
+// WithLogger reemplaza el logger del dispositivo
+func WithLogger(logger Logger) Option {
+	return func(d *Device) {
+		if logger != nil {
+			d.logger = logger
+		}
+	}
+}
+
+// WithSerialPort usa el puerto indicado en lugar de abrir el puerto serial
+// configurado. Útil para simulaciones y puertos guionizados
+func WithSerialPort(port rs485.SerialPort) Option {
+	return func(d *Device) {
+		d.port = port
+	}
+}
+
+// WithPortLock toma un bloqueo consultivo (archivo LCK..<puerto> en dir)
+// al abrir el dispositivo, para que dos procesos no controlen el mismo
+// torniquete a la vez. Si otro proceso tiene el bloqueo, Open falla con
+// un error que envuelve rs485.ErrPortLocked e indica el PID del dueño.
+// También respeta los archivos LCK.. de programas que solo escriben su PID
+// (minicom, lockdev) mientras ese proceso exista. Fuera de sistemas unix
+// Open falla con rs485.ErrLockUnsupported
+func WithPortLock(dir string) Option {
+	return func(d *Device) {
+		d.lockDir = dir
+	}
+}
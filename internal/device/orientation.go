@@ -0,0 +1,112 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidDirection = errors.New("invalid passage direction")
+
+// Orientation indica hacia qué lado físico del torniquete queda la entrada
+type Orientation int
+
+const (
+	EntryIsLeft  Orientation = iota // La entrada corresponde al paso izquierdo (default)
+	EntryIsRight                    // La entrada corresponde al paso derecho
+)
+
+func (o Orientation) String() string {
+	switch o {
+	case EntryIsLeft:
+		return "EntryIsLeft"
+	case EntryIsRight:
+		return "EntryIsRight"
+	default:
+		return fmt.Sprintf("Orientation(%d)", int(o))
+	}
+}
+
+// WithOrientation define la orientación de instalación para que las APIs
+// de entrada/salida se traduzcan al lado físico correcto
+func WithOrientation(o Orientation) Option {
+	return func(d *Device) {
+		d.orientation = o
+	}
+}
+
+// Orientation retorna la orientación de instalación configurada
+func (d *Device) Orientation() Orientation {
+	return d.orientation
+}
+
+// isLeft indica si la dirección lógica corresponde al lado izquierdo
+func (d *Device) isLeft(dir PassageDirection) (bool, error) {
+	switch dir {
+	case PassageDirectionEntry:
+		return d.orientation == EntryIsLeft, nil
+	case PassageDirectionExit:
+		return d.orientation == EntryIsRight, nil
+	default:
+		return false, fmt.Errorf("%w: %d", ErrInvalidDirection, dir)
+	}
+}
+
+// applyOrientation completa los contadores de entrada/salida del estado
+func (d *Device) applyOrientation(status *Status) {
+	if d.orientation == EntryIsRight {
+		status.EntryCount = status.RightPedestrianCount
+		status.ExitCount = status.LeftPedestrianCount
+		return
+	}
+	status.EntryCount = status.LeftPedestrianCount
+	status.ExitCount = status.RightPedestrianCount
+}
+
+// OpenPassage abre el paso en la dirección lógica indicada
+func (d *Device) OpenPassage(ctx context.Context, dir PassageDirection, value uint8) (*CommandResult, error) {
+	left, err := d.isLeft(dir)
+	if err != nil {
+		return nil, err
+	}
+	if left {
+		return d.LeftOpen(ctx, value)
+	}
+	return d.RightOpen(ctx, value)
+}
+
+// AlwaysOpenPassage mantiene siempre abierto el paso en la dirección lógica indicada
+func (d *Device) AlwaysOpenPassage(ctx context.Context, dir PassageDirection) error {
+	left, err := d.isLeft(dir)
+	if err != nil {
+		return err
+	}
+	if left {
+		return d.LeftAlwaysOpen(ctx)
+	}
+	return d.RightAlwaysOpen(ctx)
+}
+
+// ForbidPassage prohíbe el paso en la dirección lógica indicada
+func (d *Device) ForbidPassage(ctx context.Context, dir PassageDirection) error {
+	left, err := d.isLeft(dir)
+	if err != nil {
+		return err
+	}
+	if left {
+		return d.ForbiddenLeftPassage(ctx)
+	}
+	return d.ForbiddenRightPassage(ctx)
+}
+
+// ResetPassageCounters resetea el contador de la dirección lógica indicada
+func (d *Device) ResetPassageCounters(ctx context.Context, dir PassageDirection) error {
+	left, err := d.isLeft(dir)
+	if err != nil {
+		return err
+	}
+	if left {
+		return d.ResetLeftCounters(ctx)
+	}
+	return d.ResetRightCounters(ctx)
+}
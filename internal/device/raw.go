@@ -0,0 +1,134 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+var (
+	ErrRawConnClosed    = errors.New("raw connection is closed")
+	ErrConcurrentAccess = errors.New("concurrent bus access")
+)
+
+// acquireBus reserva el bus para una transacción, esperando a que termine
+// la transacción en curso o a que el contexto se cancele
+func (d *Device) acquireBus(ctx context.Context) error {
+	select {
+	case d.bus <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// tryAcquireBus reserva el bus solo si está libre
+func (d *Device) tryAcquireBus() bool {
+	select {
+	case d.bus <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseBus libera el bus reservado con acquireBus o tryAcquireBus
+func (d *Device) releaseBus() {
+	<-d.bus
+}
+
+// rawConn da acceso directo a los bytes del puerto mientras mantiene el bus
+// reservado. Los comandos quedan suspendidos hasta que se llame a Close.
+// Una lectura y una escritura pueden ir en paralelo, pero dos escrituras (o
+// dos lecturas) simultáneas intercalarían bytes y retornan ErrConcurrentAccess.
+// Queda ligada a la conexión abierta al crearla: si el dispositivo se cierra
+// (aunque se vuelva a abrir) retorna ErrDeviceClosed
+type rawConn struct {
+	d       *Device
+	conn    *rs485.Connection
+	once    sync.Once
+	mu      sync.RWMutex
+	closed  bool
+	reading atomic.Bool
+	writing atomic.Bool
+}
+
+// RawConn reserva el bus y retorna una conexión de bytes sin procesar para
+// herramientas que necesitan acceso directo (actualización de firmware,
+// diagnósticos del fabricante). Mientras la conexión esté abierta, SendCommand
+// espera. Close libera el bus sin cerrar el puerto
+func (d *Device) RawConn(ctx context.Context) (io.ReadWriteCloser, error) {
+	if !d.IsOpen() {
+		return nil, ErrDeviceNotOpen
+	}
+
+	if err := d.acquireBus(ctx); err != nil {
+		return nil, err
+	}
+
+	d.mu.RLock()
+	conn := d.conn
+	d.mu.RUnlock()
+	if conn == nil {
+		d.releaseBus()
+		return nil, ErrDeviceNotOpen
+	}
+
+	d.logger.Info("Raw connection acquired, command processing suspended")
+	return &rawConn{d: d, conn: conn}, nil
+}
+
+func (rc *rawConn) Read(p []byte) (int, error) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	if rc.closed {
+		return 0, ErrRawConnClosed
+	}
+	if !rc.reading.CompareAndSwap(false, true) {
+		return 0, ErrConcurrentAccess
+	}
+	defer rc.reading.Store(false)
+
+	rc.d.mu.RLock()
+	defer rc.d.mu.RUnlock()
+	if rc.d.closed || rc.d.conn != rc.conn {
+		return 0, ErrDeviceClosed
+	}
+	return rc.conn.Read(p)
+}
+
+func (rc *rawConn) Write(p []byte) (int, error) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	if rc.closed {
+		return 0, ErrRawConnClosed
+	}
+	if !rc.writing.CompareAndSwap(false, true) {
+		return 0, ErrConcurrentAccess
+	}
+	defer rc.writing.Store(false)
+
+	rc.d.mu.RLock()
+	defer rc.d.mu.RUnlock()
+	if rc.d.closed || rc.d.conn != rc.conn {
+		return 0, ErrDeviceClosed
+	}
+	return rc.conn.Write(p)
+}
+
+// Close libera el bus y reanuda el procesamiento de comandos
+func (rc *rawConn) Close() error {
+	rc.once.Do(func() {
+		rc.mu.Lock()
+		rc.closed = true
+		rc.mu.Unlock()
+
+		rc.d.releaseBus()
+		rc.d.logger.Info("Raw connection released, command processing resumed")
+	})
+	return nil
+}
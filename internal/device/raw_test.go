@@ -0,0 +1,114 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// blockingPort detiene cada escritura hasta que se cierre release
+type blockingPort struct {
+	*rs485.ScriptedPort
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newBlockingPort() *blockingPort {
+	return &blockingPort{
+		ScriptedPort: rs485.NewScriptedPort(),
+		entered:      make(chan struct{}, 1),
+		release:      make(chan struct{}),
+	}
+}
+
+func (p *blockingPort) Write(b []byte) (int, error) {
+	p.entered <- struct{}{}
+	<-p.release
+	return p.ScriptedPort.Write(b)
+}
+
+func TestRawConnOverlappingWrites(t *testing.T) {
+	port := newBlockingPort()
+	d := newTestDevice(t, port)
+
+	raw, err := d.RawConn(context.Background())
+	if err != nil {
+		t.Fatalf("RawConn: %v", err)
+	}
+	defer raw.Close()
+
+	done := make(chan error)
+	go func() {
+		_, err := raw.Write([]byte{0x01, 0x02})
+		done <- err
+	}()
+	<-port.entered
+
+	if _, err := raw.Write([]byte{0x03}); !errors.Is(err, ErrConcurrentAccess) {
+		t.Errorf("overlapping Write error = %v, want ErrConcurrentAccess", err)
+	}
+
+	close(port.release)
+	if err := <-done; err != nil {
+		t.Fatalf("first Write: %v", err)
+	}
+	if writes := port.Writes(); len(writes) != 1 {
+		t.Errorf("%d writes reached the port, want 1", len(writes))
+	}
+}
+
+func TestDeviceAccessDuringRawConn(t *testing.T) {
+	d := newTestDevice(t, rs485.NewScriptedPort(responseFrame(0, 0)))
+
+	raw, err := d.RawConn(context.Background())
+	if err != nil {
+		t.Fatalf("RawConn: %v", err)
+	}
+
+	buffer := make([]byte, 32)
+	if _, err := d.Read(context.Background(), buffer); !errors.Is(err, ErrConcurrentAccess) {
+		t.Errorf("Read error = %v, want ErrConcurrentAccess", err)
+	}
+	if err := d.Write([]byte{0x01}); !errors.Is(err, ErrConcurrentAccess) {
+		t.Errorf("Write error = %v, want ErrConcurrentAccess", err)
+	}
+
+	// Tras liberar el bus, el acceso directo vuelve a funcionar
+	raw.Close()
+	if _, err := d.Read(context.Background(), buffer); err != nil {
+		t.Errorf("Read after Close: %v", err)
+	}
+}
+
+func TestRawConnAfterReopen(t *testing.T) {
+	port := rs485.NewScriptedPort()
+	d := newTestDevice(t, port)
+
+	raw, err := d.RawConn(context.Background())
+	if err != nil {
+		t.Fatalf("RawConn: %v", err)
+	}
+	defer raw.Close()
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := raw.Write([]byte{0x01}); !errors.Is(err, ErrDeviceClosed) {
+		t.Errorf("Write on closed device error = %v, want ErrDeviceClosed", err)
+	}
+
+	if err := d.Open(); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := raw.Write([]byte{0x01}); !errors.Is(err, ErrDeviceClosed) {
+		t.Errorf("Write after reopen error = %v, want ErrDeviceClosed", err)
+	}
+	if _, err := raw.Read(make([]byte, 8)); !errors.Is(err, ErrDeviceClosed) {
+		t.Errorf("Read after reopen error = %v, want ErrDeviceClosed", err)
+	}
+	if writes := port.Writes(); len(writes) != 0 {
+		t.Errorf("%d writes reached the port, want 0", len(writes))
+	}
+}
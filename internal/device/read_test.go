@@ -0,0 +1,157 @@
+package device
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+const testDeviceID = 0x01
+
+// responseFrame construye una respuesta exitosa del dispositivo de prueba
+// con los contadores indicados y checksum RX válido
+func responseFrame(left, right uint32) []byte {
+	frame := make([]byte, protocol.ResponseSize)
+	frame[0] = protocol.ResponseHeader
+	frame[1] = 0x01
+	frame[2] = testDeviceID
+	putCount(frame[6:9], left)
+	putCount(frame[9:12], right)
+	frame[12] = 0xF0
+	frame[13] = protocol.SuccessExecution
+	frame[14] = 0x20
+	setChecksum(frame)
+	return frame
+}
+
+// setChecksum recalcula el checksum RX de una respuesta modificada
+func setChecksum(frame []byte) {
+	frame[protocol.ResponseSize-1] = protocol.ChecksumTx(frame[1 : protocol.ResponseSize-1])
+}
+
+// newTestDevice crea y abre un dispositivo sobre port, sin reintentos
+func newTestDevice(t *testing.T, port rs485.SerialPort, opts ...Option) *Device {
+	t.Helper()
+	d, err := New(validConfig(), append([]Option{WithSerialPort(port)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := d.Open(); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+// concat une fragmentos en una sola lectura
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestReadFrameReassembly(t *testing.T) {
+	frame := responseFrame(5, 7)
+	echo, err := protocol.BuildCommand(testDeviceID, protocol.CmdGetStatus, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noise := []byte{0x00, 0xFF, 0x13, 0x55}
+
+	tests := []struct {
+		name   string
+		chunks [][]byte
+	}{
+		{"one byte per read", rs485.SplitEvery(frame, 1)},
+		{"split across chunk boundaries", [][]byte{frame[:5], frame[5:13], frame[13:]}},
+		{"larger than read chunk", [][]byte{concat(bytes.Repeat([]byte{0x00}, DefaultReadChunkSize-4), frame)}},
+		{"noise prefixed", [][]byte{concat(noise, frame[:3]), frame[3:]}},
+		{"echo interleaved", [][]byte{echo, frame}},
+		{"echo in same read", [][]byte{concat(echo, frame[:10]), frame[10:]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := rs485.NewScriptedPort(tt.chunks...)
+			d := newTestDevice(t, port)
+
+			status, err := d.GetStatus(context.Background())
+			if err != nil {
+				t.Fatalf("GetStatus: %v", err)
+			}
+			if status.LeftPedestrianCount != 5 || status.RightPedestrianCount != 7 {
+				t.Errorf("counts = %d/%d, want 5/7", status.LeftPedestrianCount, status.RightPedestrianCount)
+			}
+			if n := len(port.Writes()); n != 1 {
+				t.Errorf("%d commands written, want 1", n)
+			}
+		})
+	}
+}
+
+func TestReadFrameHeaderSplit(t *testing.T) {
+	frame := responseFrame(5, 7)
+
+	tests := []struct {
+		name   string
+		chunks [][]byte
+	}{
+		{"burst ends at header", [][]byte{frame[:1], frame[1:]}},
+		{"noise then header", [][]byte{{0x00, 0x13, protocol.ResponseHeader}, frame[1:]}},
+		{"header then bytes in small bursts", append([][]byte{{0x13, protocol.ResponseHeader}}, rs485.SplitEvery(frame[1:], 3)...)},
+		{"stale header before split", [][]byte{{protocol.ResponseHeader, 0x09, 0x09, 0x00, protocol.ResponseHeader}, frame[1:]}},
+		{"stale header in same burst", [][]byte{concat([]byte{protocol.ResponseHeader, 0x00, 0x05}, frame)}},
+		{"stale header one byte per read", rs485.SplitEvery(concat([]byte{protocol.ResponseHeader, 0x00, 0x05, protocol.ResponseHeader, 0x00}, frame), 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDevice(t, rs485.NewScriptedPort(tt.chunks...))
+
+			status, err := d.GetStatus(context.Background())
+			if err != nil {
+				t.Fatalf("GetStatus: %v", err)
+			}
+			if status.LeftPedestrianCount != 5 || status.RightPedestrianCount != 7 {
+				t.Errorf("counts = %d/%d, want 5/7", status.LeftPedestrianCount, status.RightPedestrianCount)
+			}
+		})
+	}
+}
+
+func TestReadFrameForeignMachineNumber(t *testing.T) {
+	frame := responseFrame(5, 7)
+	frame[2] = testDeviceID + 1
+	d := newTestDevice(t, rs485.NewScriptedPort(frame))
+
+	_, err := d.GetStatus(context.Background())
+	if !errors.Is(err, protocol.ErrMachineIDMismatch) {
+		t.Fatalf("GetStatus error = %v, want ErrMachineIDMismatch", err)
+	}
+}
+
+func TestReadFrameSmallChunksAfterNoise(t *testing.T) {
+	frame := responseFrame(5, 7)
+	echo, err := protocol.BuildCommand(testDeviceID, protocol.CmdGetStatus, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Eco más ruido que ocupan más lecturas de un byte de las que caben en
+	// un límite fijo de lecturas
+	prefix := concat(echo, bytes.Repeat([]byte{0x00}, 40))
+
+	d := newTestDevice(t, rs485.NewScriptedPort(concat(prefix, frame)), WithReadBuffer(1, DefaultMaxReadBuffer))
+
+	status, err := d.GetStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if status.LeftPedestrianCount != 5 {
+		t.Errorf("left count = %d, want 5", status.LeftPedestrianCount)
+	}
+	if stats := d.Stats(); stats.Collisions != 0 {
+		t.Errorf("Collisions = %d, want 0", stats.Collisions)
+	}
+}
@@ -0,0 +1,99 @@
+package device
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+const (
+	// counterModulus es el rango de los contadores de paso (3 bytes)
+	counterModulus = 1 << 24
+
+	// rolloverWindow es el mayor avance creíble de un contador entre dos
+	// respuestas. Una bajada que equivale a un avance menor, pasando por
+	// 0xFFFFFF, es un desborde del contador y no un reinicio
+	rolloverWindow = 1 << 16
+)
+
+// restartTracker detecta reinicios del equipo comparando cada respuesta
+// con la anterior. Los contadores de paso son acumulativos: solo bajan con
+// un reset de contadores, un reinicio o al desbordar los 24 bits. Un cambio
+// de versión de firmware también implica un reinicio. Los reinicios pedidos
+// con el comando Reset se cuentan aparte
+type restartTracker struct {
+	mu        sync.Mutex
+	seen      bool
+	version   byte
+	left      uint32
+	right     uint32
+	count     uint64
+	commanded uint64
+	last      time.Time
+}
+
+// observe actualiza el seguimiento con la respuesta al comando cmd.
+// Retorna true si la respuesta revela un reinicio no solicitado
+func (t *restartTracker) observe(cmd protocol.CommandType, resp *protocol.Response) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	left, right := resp.GetLeftCount(), resp.GetRightCount()
+	restarted := false
+
+	// Tras un reset propio el contador respectivo parte de cero, tanto si
+	// la respuesta ya lo refleja como si trae aún el valor previo
+	switch cmd {
+	case protocol.CmdResetLeftCounters:
+		t.left, left = 0, 0
+	case protocol.CmdResetRightCounters:
+		t.right, right = 0, 0
+	}
+
+	switch {
+	case cmd == protocol.CmdRestartDevice:
+		// Reinicio solicitado: los contadores vuelven a cero
+		t.commanded++
+		left, right = 0, 0
+	case !t.seen:
+	case resp.VersionNumber != t.version:
+		restarted = true
+	default:
+		restarted = counterDropped(t.left, left) || counterDropped(t.right, right)
+	}
+
+	t.seen = true
+	t.version = resp.VersionNumber
+	t.left, t.right = left, right
+	if restarted {
+		t.count++
+		t.last = resp.ReceivedAt
+	}
+	return restarted
+}
+
+// counterDropped indica si el contador bajó de prev a cur por algo distinto
+// de un desborde
+func counterDropped(prev, cur uint32) bool {
+	if cur >= prev {
+		return false
+	}
+	return cur+counterModulus-prev > rolloverWindow
+}
+
+// firmware retorna la versión de firmware de la última respuesta y si ya
+// hubo alguna
+func (t *restartTracker) firmware() (byte, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.version, t.seen
+}
+
+// snapshot retorna el número de reinicios no solicitados, el instante del
+// último y el número de reinicios pedidos con Reset
+func (t *restartTracker) snapshot() (uint64, time.Time, uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count, t.last, t.commanded
+}
@@ -0,0 +1,101 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// countsResponse retorna una respuesta con los contadores y versión dados
+func countsResponse(left, right uint32, version byte) *protocol.Response {
+	resp := &protocol.Response{VersionNumber: version}
+	putCount(resp.LeftPedestrianCount[:], left)
+	putCount(resp.RightPedestrianCount[:], right)
+	return resp
+}
+
+func TestRestartTracker(t *testing.T) {
+	type step struct {
+		cmd         protocol.CommandType
+		left, right uint32
+		version     byte
+	}
+
+	tests := []struct {
+		name          string
+		steps         []step
+		wantRestarts  uint64
+		wantCommanded uint64
+	}{
+		{
+			name: "counters advance",
+			steps: []step{
+				{protocol.CmdGetStatus, 10, 20, 1},
+				{protocol.CmdGetStatus, 11, 20, 1},
+			},
+		},
+		{
+			name: "counters drop",
+			steps: []step{
+				{protocol.CmdGetStatus, 1000, 20, 1},
+				{protocol.CmdGetStatus, 3, 0, 1},
+			},
+			wantRestarts: 1,
+		},
+		{
+			name: "firmware version changes",
+			steps: []step{
+				{protocol.CmdGetStatus, 10, 20, 1},
+				{protocol.CmdGetStatus, 10, 20, 2},
+			},
+			wantRestarts: 1,
+		},
+		{
+			name: "commanded reset",
+			steps: []step{
+				{protocol.CmdGetStatus, 10, 20, 1},
+				{protocol.CmdRestartDevice, 10, 20, 1},
+				{protocol.CmdGetStatus, 0, 0, 1},
+			},
+			wantCommanded: 1,
+		},
+		{
+			name: "left reset ack carries old count",
+			steps: []step{
+				{protocol.CmdGetStatus, 10, 20, 1},
+				{protocol.CmdResetLeftCounters, 10, 20, 1},
+				{protocol.CmdGetStatus, 0, 20, 1},
+			},
+		},
+		{
+			name: "right reset then right drop",
+			steps: []step{
+				{protocol.CmdGetStatus, 10, 20, 1},
+				{protocol.CmdResetRightCounters, 10, 0, 1},
+				{protocol.CmdGetStatus, 10, 5, 1},
+				{protocol.CmdGetStatus, 10, 1, 1},
+			},
+			wantRestarts: 1,
+		},
+		{
+			name: "24-bit rollover",
+			steps: []step{
+				{protocol.CmdGetStatus, counterModulus - 2, 20, 1},
+				{protocol.CmdGetStatus, 3, 20, 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tracker restartTracker
+			for _, s := range tt.steps {
+				tracker.observe(s.cmd, countsResponse(s.left, s.right, s.version))
+			}
+			restarts, _, commanded := tracker.snapshot()
+			if restarts != tt.wantRestarts || commanded != tt.wantCommanded {
+				t.Errorf("restarts/commanded = %d/%d, want %d/%d", restarts, commanded, tt.wantRestarts, tt.wantCommanded)
+			}
+		})
+	}
+}
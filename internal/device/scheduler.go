@@ -0,0 +1,325 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// ErrPreempted indica una consulta de telemetría que abandonó sus
+// reintentos para ceder el bus a un comando de control (ver WithTelemetryBudget)
+var ErrPreempted = errors.New("telemetry preempted by control command")
+
+// DefaultTelemetryShare es la fracción del tiempo de bus que la telemetría
+// puede usar por defecto con WithTelemetryBudget
+const DefaultTelemetryShare = 0.5
+
+// telemetryWindow es la ventana sobre la que se mide el presupuesto: la
+// telemetría puede acumular hasta share*telemetryWindow de tiempo de bus
+const telemetryWindow = time.Second
+
+// trafficClass distingue el tráfico que actúa sobre la puerta de las consultas
+type trafficClass int
+
+const (
+	trafficControl   trafficClass = iota // Comandos sobre la puerta o los contadores
+	trafficTelemetry                     // Consultas de estado (GetStatus)
+)
+
+func classOf(cmd protocol.CommandType) trafficClass {
+	if cmd == protocol.CmdGetStatus {
+		return trafficTelemetry
+	}
+	return trafficControl
+}
+
+// WithTelemetryBudget reparte el bus entre control y telemetría en enlaces
+// lentos, donde un GetStatus ocupa decenas de milisegundos:
+//
+//   - los comandos de control pasan delante de las consultas en espera;
+//   - la telemetría usa como máximo share del tiempo de bus (0 < share <= 1,
+//     DefaultTelemetryShare si está fuera de rango); al agotarlo espera;
+//   - una consulta en curso no inicia más reintentos si un comando de
+//     control lleva esperando y ella ya ocupó el bus maxControlDelay o más.
+//     Termina con ErrPreempted.
+//
+// Así un comando de control espera a lo sumo maxControlDelay más un intento
+// de telemetría (escritura y ReadTimeout). A cambio, un flujo continuo de
+// comandos de control retrasa la telemetría sin límite. Las esperas
+// logradas se reportan en Stats. Sin esta opción el bus se entrega por
+// orden de llegada
+func WithTelemetryBudget(share float64, maxControlDelay time.Duration) Option {
+	return func(d *Device) {
+		if share <= 0 || share > 1 {
+			share = DefaultTelemetryShare
+		}
+		if maxControlDelay < 0 {
+			maxControlDelay = 0
+		}
+		d.sched.enabled = true
+		d.sched.share = share
+		d.sched.maxDelay = maxControlDelay
+		d.sched.credit = d.sched.budget()
+	}
+}
+
+// scheduler decide qué clase de tráfico toma el bus
+type scheduler struct {
+	enabled  bool
+	share    float64
+	maxDelay time.Duration
+
+	mu       sync.Mutex
+	credit   time.Duration // Tiempo de bus disponible para telemetría
+	refilled time.Time     // Última recarga de credit
+	control  int           // Comandos de control esperando el bus
+	holder   trafficClass  // Clase de la transacción que tiene el bus
+	held     time.Time     // Desde cuándo la tiene
+	wake     chan struct{} // Se cierra cuando cambia el estado
+
+	waits       [2]waitStats  // Espera por el bus, por clase
+	preemptions atomic.Uint64 // Consultas que cedieron el bus
+}
+
+// waitStats acumula los tiempos de espera por el bus
+type waitStats struct {
+	count atomic.Int64
+	total atomic.Int64
+	max   atomic.Int64
+}
+
+func (w *waitStats) observe(wait time.Duration) {
+	w.count.Add(1)
+	w.total.Add(int64(wait))
+	for {
+		m := w.max.Load()
+		if int64(wait) <= m || w.max.CompareAndSwap(m, int64(wait)) {
+			return
+		}
+	}
+}
+
+func (w *waitStats) snapshot() (avg, max time.Duration) {
+	if n := w.count.Load(); n > 0 {
+		avg = time.Duration(w.total.Load() / n)
+	}
+	return avg, time.Duration(w.max.Load())
+}
+
+func (s *scheduler) budget() time.Duration {
+	return time.Duration(float64(telemetryWindow) * s.share)
+}
+
+// refill recarga el crédito de telemetría. s.mu debe estar tomado
+func (s *scheduler) refill(now time.Time) {
+	if !s.refilled.IsZero() {
+		s.credit += time.Duration(math.Round(float64(now.Sub(s.refilled)) * s.share))
+	}
+	s.refilled = now
+	if budget := s.budget(); s.credit > budget {
+		s.credit = budget
+	}
+}
+
+// waitChan retorna el canal que se cierra en el próximo cambio de estado.
+// s.mu debe estar tomado
+func (s *scheduler) waitChan() chan struct{} {
+	if s.wake == nil {
+		s.wake = make(chan struct{})
+	}
+	return s.wake
+}
+
+// broadcast despierta a la telemetría en espera. s.mu debe estar tomado
+func (s *scheduler) broadcast() {
+	if s.wake != nil {
+		close(s.wake)
+		s.wake = nil
+	}
+}
+
+// admit indica si una consulta puede pedir el bus ya (0), cuánto debe
+// esperar a que se recargue el crédito (> 0) o si debe esperar a que
+// pasen los comandos de control (< 0). wake se cierra al cambiar el estado
+func (s *scheduler) admit(now time.Time) (wait time.Duration, wake <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.control > 0 {
+		return -1, s.waitChan()
+	}
+	s.refill(now)
+	if s.credit >= 0 {
+		return 0, nil
+	}
+	// Redondear hacia arriba: tras esperar wait, refill recupera al menos
+	// el crédito que falta
+	return time.Duration(math.Ceil(float64(-s.credit) / s.share)), s.waitChan()
+}
+
+func (s *scheduler) controlWaiting(delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.control += delta
+	s.broadcast()
+}
+
+func (s *scheduler) controlPending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.control > 0
+}
+
+// acquireBusFor reserva el bus para una transacción de la clase indicada
+// y registra cuánto tuvo que esperar
+func (d *Device) acquireBusFor(ctx context.Context, class trafficClass) error {
+	start := d.clock.Now()
+	if err := d.acquireScheduled(ctx, class); err != nil {
+		return err
+	}
+
+	now := d.clock.Now()
+	d.sched.waits[class].observe(now.Sub(start))
+	d.sched.mu.Lock()
+	d.sched.holder = class
+	d.sched.held = now
+	d.sched.mu.Unlock()
+	return nil
+}
+
+func (d *Device) acquireScheduled(ctx context.Context, class trafficClass) error {
+	s := &d.sched
+	if !s.enabled {
+		return d.acquireBus(ctx)
+	}
+	if class == trafficControl {
+		s.controlWaiting(1)
+		defer s.controlWaiting(-1)
+		return d.acquireBus(ctx)
+	}
+
+	for {
+		wait, wake := s.admit(d.clock.Now())
+		if wait == 0 {
+			if err := d.acquireBus(ctx); err != nil {
+				return err
+			}
+			if !s.controlPending() {
+				return nil
+			}
+			// Llegó un comando de control mientras se esperaba el bus
+			d.releaseBus()
+			continue
+		}
+
+		var refilled <-chan time.Time
+		if wait > 0 {
+			refilled = d.clock.After(wait)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-refilled:
+		}
+	}
+}
+
+// releaseBusFor descuenta el tiempo de bus usado por la telemetría y libera el bus
+func (d *Device) releaseBusFor(class trafficClass) {
+	s := &d.sched
+	if s.enabled && class == trafficTelemetry {
+		now := d.clock.Now()
+		s.mu.Lock()
+		s.refill(now)
+		s.credit -= now.Sub(s.held)
+		s.mu.Unlock()
+	}
+	d.releaseBus()
+}
+
+// busHold es la reserva del bus de un comando, que puede soltarse y
+// retomarse mientras el comando espera (ver WithBusyPolicy). Al soltar el
+// bus retira los cambios de estado pendientes y acumula los reintentos
+// hechos con el bus reservado, que son solo de este comando
+type busHold struct {
+	d       *Device
+	class   trafficClass
+	held    bool
+	retries uint64        // Reintentos del comando
+	before  uint64        // Reintentos totales al reservar el bus
+	changes []stateChange // Cambios de estado por notificar
+}
+
+func (h *busHold) acquire(ctx context.Context) error {
+	if err := h.d.acquireBusFor(ctx, h.class); err != nil {
+		return err
+	}
+	h.held = true
+	h.before = h.d.stats.retries.Load()
+	return nil
+}
+
+// release suelta el bus si está reservado
+func (h *busHold) release() {
+	if !h.held {
+		return
+	}
+	h.retries += h.d.stats.retries.Load() - h.before
+	h.changes = append(h.changes, h.d.takeChanges()...)
+	h.held = false
+	h.d.releaseBusFor(h.class)
+}
+
+// yieldState indica si la transacción de telemetría en curso debe ceder el
+// bus ya; si no, en cuánto tiempo deberá hacerlo (0: no hay control en
+// espera) y el canal que avisa cuando llegue uno. Solo la llama quien tiene
+// el bus
+func (d *Device) yieldState() (yield bool, after time.Duration, wake <-chan struct{}) {
+	s := &d.sched
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.holder != trafficTelemetry {
+		return false, 0, nil
+	}
+	if s.control > 0 {
+		after = s.maxDelay - d.clock.Now().Sub(s.held)
+		if after <= 0 {
+			return true, 0, nil
+		}
+	}
+	return false, after, s.waitChan()
+}
+
+// retryWait espera delay antes de un reintento. Si la transacción es de
+// telemetría y durante la espera debe ceder el bus a un comando de
+// control, retorna de inmediato con yield
+func (d *Device) retryWait(ctx context.Context, delay time.Duration) (yield bool, err error) {
+	if !d.sched.enabled {
+		return false, d.sleep(ctx, delay)
+	}
+
+	done := d.clock.After(delay)
+	for {
+		yield, after, wake := d.yieldState()
+		if yield {
+			return true, nil
+		}
+		var due <-chan time.Time
+		if after > 0 {
+			due = d.clock.After(after)
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-done:
+			return false, nil
+		case <-wake:
+		case <-due:
+		}
+	}
+}
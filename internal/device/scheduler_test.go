@@ -0,0 +1,249 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// manualClock es un reloj que solo avanza con Advance. Las esperas de
+// After quedan pendientes hasta que la hora llegue a su vencimiento
+type manualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []manualTimer
+}
+
+type manualTimer struct {
+	due time.Time
+	ch  chan time.Time
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, manualTimer{due: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance adelanta la hora y dispara las esperas vencidas
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.due.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.ch <- c.now
+	}
+	c.timers = pending
+}
+
+// waitTimers espera hasta que haya n esperas pendientes
+func (c *manualClock) waitTimers(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.mu.Lock()
+		got := len(c.timers)
+		c.mu.Unlock()
+		if got >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d pending timers, want %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitControl espera hasta que haya un comando de control pidiendo el bus
+func waitControl(t *testing.T, d *Device) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !d.sched.controlPending() {
+		if time.Now().After(deadline) {
+			t.Fatal("control command never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSchedulerControlGoesFirst(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDevice(t, rs485.NewScriptedPort(), WithClock(newManualClock()), WithTelemetryBudget(0.5, 0))
+
+	// El bus está ocupado por una consulta; llegan otra consulta y un
+	// comando de control
+	if err := d.acquireBusFor(ctx, trafficTelemetry); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan trafficClass, 2)
+	release := make(chan struct{})
+	take := func(class trafficClass) {
+		if err := d.acquireBusFor(ctx, class); err != nil {
+			t.Error(err)
+			return
+		}
+		order <- class
+		<-release
+		d.releaseBusFor(class)
+	}
+	go take(trafficTelemetry)
+	time.Sleep(10 * time.Millisecond) // La consulta queda en cola primero
+	go take(trafficControl)
+	waitControl(t, d)
+
+	d.releaseBusFor(trafficTelemetry)
+	if first := <-order; first != trafficControl {
+		t.Errorf("bus went to class %d first, want control", first)
+	}
+	release <- struct{}{}
+	if second := <-order; second != trafficTelemetry {
+		t.Errorf("bus went to class %d second, want telemetry", second)
+	}
+	release <- struct{}{}
+}
+
+func TestSchedulerPreemptsTelemetryRetries(t *testing.T) {
+	const maxDelay = 50 * time.Millisecond
+	ctx := context.Background()
+	clock := newManualClock()
+	// La consulta nunca recibe respuesta; el comando de control sí
+	d := newTestDevice(t, replyOn(map[int][][]byte{2: {responseFrame(1, 0)}}),
+		WithClock(clock), WithTelemetryBudget(0.5, maxDelay))
+	d.config.RetryCount = 3
+
+	status := make(chan error, 1)
+	go func() {
+		_, err := d.GetStatus(ctx)
+		status <- err
+	}()
+	clock.waitTimers(t, 1) // Espera antes del primer reintento
+
+	control := make(chan error, 1)
+	go func() {
+		_, err := d.LeftOpen(ctx, 1)
+		control <- err
+	}()
+	clock.waitTimers(t, 2) // El reintento debe ceder en maxDelay
+
+	select {
+	case err := <-status:
+		t.Fatalf("telemetry gave up before maxControlDelay: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(maxDelay)
+	if err := <-status; !errors.Is(err, ErrPreempted) {
+		t.Fatalf("GetStatus error = %v, want ErrPreempted", err)
+	}
+	if err := <-control; err != nil {
+		t.Fatalf("LeftOpen: %v", err)
+	}
+
+	stats := d.Stats()
+	if stats.Preemptions != 1 {
+		t.Errorf("Preemptions = %d, want 1", stats.Preemptions)
+	}
+	if stats.ControlWaitMax != maxDelay || stats.ControlWaitAvg != maxDelay {
+		t.Errorf("control wait avg/max = %v/%v, want %v", stats.ControlWaitAvg, stats.ControlWaitMax, maxDelay)
+	}
+	if stats.TelemetryWaitMax != 0 {
+		t.Errorf("TelemetryWaitMax = %v, want 0", stats.TelemetryWaitMax)
+	}
+}
+
+func TestSchedulerRetryWaitYield(t *testing.T) {
+	ctx := context.Background()
+	clock := newManualClock()
+	d := newTestDevice(t, rs485.NewScriptedPort(), WithClock(clock), WithTelemetryBudget(0.5, 0))
+
+	if err := d.acquireBusFor(ctx, trafficTelemetry); err != nil {
+		t.Fatal(err)
+	}
+	defer d.releaseBusFor(trafficTelemetry)
+
+	// Sin control en espera, el reintento espera su retardo completo
+	done := make(chan bool, 1)
+	go func() {
+		yield, err := d.retryWait(ctx, 100*time.Millisecond)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- yield
+	}()
+	clock.waitTimers(t, 1)
+	clock.Advance(100 * time.Millisecond)
+	if <-done {
+		t.Error("retryWait yielded without a control command waiting")
+	}
+
+	// Con un comando de control en espera y maxControlDelay cero, cede ya
+	d.sched.controlWaiting(1)
+	defer d.sched.controlWaiting(-1)
+	if yield, err := d.retryWait(ctx, 100*time.Millisecond); err != nil || !yield {
+		t.Errorf("retryWait = %v, %v; want yield", yield, err)
+	}
+}
+
+func TestSchedulerCreditRefill(t *testing.T) {
+	ctx := context.Background()
+	clock := newManualClock()
+	d := newTestDevice(t, replyOn(map[int][][]byte{1: {responseFrame(0, 0)}}),
+		WithClock(clock), WithTelemetryBudget(0.5, 0))
+
+	// Una consulta ocupa el bus 600ms: con share 0.5 el presupuesto es de
+	// 500ms y se recargan 300ms mientras tanto, así que queda en -100ms
+	if err := d.acquireBusFor(ctx, trafficTelemetry); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(600 * time.Millisecond)
+	d.releaseBusFor(trafficTelemetry)
+
+	status := make(chan error, 1)
+	go func() {
+		_, err := d.GetStatus(ctx)
+		status <- err
+	}()
+	clock.waitTimers(t, 1)
+
+	// Recuperar 100ms de crédito a share 0.5 toma 200ms
+	clock.Advance(200*time.Millisecond - time.Nanosecond)
+	select {
+	case err := <-status:
+		t.Fatalf("telemetry ran before its credit refilled: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(time.Nanosecond)
+	if err := <-status; err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+
+	want := 200 * time.Millisecond
+	if got := d.Stats().TelemetryWaitMax; got != want {
+		t.Errorf("TelemetryWaitMax = %v, want %v", got, want)
+	}
+	if got, wantAvg := d.Stats().TelemetryWaitAvg, want/2; got != wantAvg {
+		t.Errorf("TelemetryWaitAvg = %v, want %v", got, wantAvg)
+	}
+}
@@ -0,0 +1,97 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Step representa un paso de una secuencia de comandos con compensación
+type Step struct {
+	Name string                          // Nombre descriptivo del paso
+	Do   func(ctx context.Context) error // Acción a ejecutar
+	Undo func(ctx context.Context) error // Acción compensatoria (opcional)
+}
+
+// Sequence es una lista ordenada de pasos que se completa entera o se
+// compensa. No es atómica frente a otros comandos: fuera de las secuencias,
+// cualquier comando puede ejecutarse entre dos pasos
+type Sequence []Step
+
+// SequenceError describe el fallo de una secuencia y el resultado del rollback
+type SequenceError struct {
+	Index       int    // Índice del paso que falló
+	Name        string // Nombre del paso que falló
+	Err         error  // Error original del paso
+	RollbackErr error  // Errores producidos durante la compensación (nil si fue completa)
+}
+
+func (e *SequenceError) Error() string {
+	msg := fmt.Sprintf("sequence step %d (%s) failed: %v", e.Index, e.Name, e.Err)
+	if e.RollbackErr != nil {
+		msg += fmt.Sprintf("; rollback incomplete: %v", e.RollbackErr)
+	}
+	return msg
+}
+
+func (e *SequenceError) Unwrap() error {
+	return e.Err
+}
+
+// Do ejecuta los pasos de la secuencia en orden. Si un paso falla, aplica
+// las acciones compensatorias de los pasos ya ejecutados en orden inverso
+// para que la puerta no quede configurada a medias. Una secuencia con algún
+// paso sin acción se rechaza antes de ejecutar nada. Dos secuencias no se
+// intercalan entre sí, pero los comandos sueltos de otras goroutines sí
+// pueden ejecutarse entre sus pasos
+func (d *Device) Do(ctx context.Context, seq Sequence) error {
+	for i, step := range seq {
+		if step.Do == nil {
+			return fmt.Errorf("sequence step %d (%s) has no action", i, step.Name)
+		}
+	}
+
+	d.seqMu.Lock()
+	defer d.seqMu.Unlock()
+
+	for i, step := range seq {
+		err := ctx.Err()
+		if err == nil {
+			err = step.Do(ctx)
+		}
+		if err == nil {
+			continue
+		}
+
+		d.logger.Warn("Sequence step failed, rolling back", "step", step.Name, "error", err)
+		return &SequenceError{
+			Index:       i,
+			Name:        step.Name,
+			Err:         err,
+			RollbackErr: d.rollback(ctx, seq[:i]),
+		}
+	}
+
+	return nil
+}
+
+// rollback aplica las compensaciones de los pasos completados en orden inverso.
+// Se ejecuta aunque el contexto original haya sido cancelado
+func (d *Device) rollback(ctx context.Context, done Sequence) error {
+	rbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), d.config.Timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(done) - 1; i >= 0; i-- {
+		step := done[i]
+		if step.Undo == nil {
+			continue
+		}
+		if err := step.Undo(rbCtx); err != nil {
+			d.logger.Error("Sequence rollback failed", "step", step.Name, "error", err)
+			errs = append(errs, fmt.Errorf("undo %s: %w", step.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
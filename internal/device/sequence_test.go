@@ -0,0 +1,56 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSequenceRollback(t *testing.T) {
+	d := &Device{config: &Config{Timeout: time.Second}, logger: GetDefaultLogger()}
+	failed := errors.New("step failed")
+
+	var calls []string
+	step := func(name string, err error) Step {
+		return Step{
+			Name: name,
+			Do: func(context.Context) error {
+				calls = append(calls, "do "+name)
+				return err
+			},
+			Undo: func(context.Context) error {
+				calls = append(calls, "undo "+name)
+				return nil
+			},
+		}
+	}
+
+	err := d.Do(context.Background(), Sequence{step("a", nil), step("b", nil), step("c", failed)})
+
+	var seqErr *SequenceError
+	if !errors.As(err, &seqErr) || seqErr.Index != 2 || !errors.Is(err, failed) {
+		t.Fatalf("Do error = %v, want SequenceError at step 2", err)
+	}
+	want := []string{"do a", "do b", "do c", "undo b", "undo a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestSequenceMissingActionRunsNothing(t *testing.T) {
+	d := &Device{config: &Config{Timeout: time.Second}, logger: GetDefaultLogger()}
+
+	ran := false
+	err := d.Do(context.Background(), Sequence{
+		{Name: "first", Do: func(context.Context) error { ran = true; return nil }},
+		{Name: "second"},
+	})
+	if err == nil {
+		t.Fatal("Do accepted a step without action")
+	}
+	if ran {
+		t.Error("steps ran before the sequence was rejected")
+	}
+}
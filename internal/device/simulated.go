@@ -0,0 +1,85 @@
+package device
+
+import (
+	"sync"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// Valores de reposo del simulador, tomados de una trama real de estado
+const (
+	simVersion  = 0x01
+	simInfrared = 0xF0
+	simVoltage  = 0x20
+)
+
+// simulator responde cada comando con una trama de respuesta verosímil.
+// Mantiene los contadores de paso: una apertura simula el paso de un
+// peatón por ese lado y los resets los ponen en cero
+type simulator struct {
+	mu    sync.Mutex
+	left  uint32
+	right uint32
+}
+
+// WithSimulatedResponses reemplaza el transporte por un puerto simulado
+// que responde a todos los comandos sin hardware ni simulador externo.
+// Pensado para demos y pruebas de interfaz; no reproduce tiempos, fallas
+// ni alarmas del equipo real
+func WithSimulatedResponses() Option {
+	return func(d *Device) {
+		sim := &simulator{}
+		port := rs485.NewScriptedPort()
+		port.OnWrite = sim.respond
+		d.port = port
+	}
+}
+
+// respond interpreta la trama de comando y retorna la respuesta. Las tramas
+// mal formadas o con checksum TX inválido no se responden, como haría el
+// equipo
+func (s *simulator) respond(frame []byte) [][]byte {
+	if len(frame) != protocol.FrameSize || frame[0] != protocol.FrameHeader ||
+		frame[protocol.FrameSize-1] != protocol.ChecksumTx(frame[:protocol.FrameSize-1]) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch protocol.CommandType(frame[3]) {
+	case protocol.CmdLeftOpen:
+		s.left++
+	case protocol.CmdRightOpen:
+		s.right++
+	case protocol.CmdResetLeftCounters:
+		s.left = 0
+	case protocol.CmdResetRightCounters:
+		s.right = 0
+	case protocol.CmdRestartDevice:
+		s.left, s.right = 0, 0
+	}
+
+	resp := make([]byte, protocol.ResponseSize)
+	resp[0] = protocol.ResponseHeader
+	resp[1] = simVersion
+	resp[2] = frame[2] // Machine Number del comando
+	putCount(resp[6:9], s.left)
+	putCount(resp[9:12], s.right)
+	resp[12] = simInfrared
+	resp[13] = protocol.SuccessExecution
+	resp[14] = simVoltage
+
+	// Checksum RX (ver protocol.VerifyRx)
+	resp[protocol.ResponseSize-1] = protocol.ChecksumTx(resp[1 : protocol.ResponseSize-1])
+
+	return [][]byte{resp}
+}
+
+// putCount escribe un contador de 3 bytes (big endian)
+func putCount(dst []byte, n uint32) {
+	dst[0] = byte(n >> 16)
+	dst[1] = byte(n >> 8)
+	dst[2] = byte(n)
+}
@@ -0,0 +1,34 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+func TestSimulatorResponses(t *testing.T) {
+	sim := &simulator{}
+
+	for _, cmd := range []protocol.CommandType{protocol.CmdGetStatus, protocol.CmdLeftOpen, protocol.CmdRightOpen} {
+		frame, err := protocol.BuildCommand(testDeviceID, cmd, nil)
+		if err != nil {
+			t.Fatalf("BuildCommand(%v): %v", cmd, err)
+		}
+		replies := sim.respond(frame)
+		if len(replies) != 1 {
+			t.Fatalf("%v: %d replies, want 1", cmd, len(replies))
+		}
+		if err := protocol.VerifyRx(replies[0]); err != nil {
+			t.Errorf("%v: VerifyRx: %v", cmd, err)
+		}
+	}
+
+	frame, err := protocol.BuildCommand(testDeviceID, protocol.CmdGetStatus, nil)
+	if err != nil {
+		t.Fatalf("BuildCommand: %v", err)
+	}
+	frame[protocol.FrameSize-1]++
+	if replies := sim.respond(frame); replies != nil {
+		t.Errorf("bad TX checksum answered with %d replies, want none", len(replies))
+	}
+}
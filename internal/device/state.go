@@ -0,0 +1,270 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// TurnstileState es el estado del torniquete desde el punto de vista del
+// controlador. Es compartido por los módulos de nivel superior (control de
+// acceso, programación horaria, enclavamientos) a través de StateMachine
+type TurnstileState int
+
+const (
+	StateIdle       TurnstileState = iota // En reposo, paso cerrado
+	StateGranted                          // Paso autorizado, esperando al peatón
+	StatePassing                          // Peatón cruzando
+	StateClosing                          // Cierre en curso
+	StateAlwaysOpen                       // Paso siempre abierto
+	StateForbidden                        // Paso prohibido
+	StateFault                            // Falla reportada por el equipo
+)
+
+func (s TurnstileState) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateGranted:
+		return "granted"
+	case StatePassing:
+		return "passing"
+	case StateClosing:
+		return "closing"
+	case StateAlwaysOpen:
+		return "always-open"
+	case StateForbidden:
+		return "forbidden"
+	case StateFault:
+		return "fault"
+	default:
+		return fmt.Sprintf("TurnstileState(%d)", int(s))
+	}
+}
+
+// transitions es la tabla de transiciones válidas. Un paso solo puede
+// empezar con el torniquete autorizado o siempre abierto, y de una falla
+// solo se sale volviendo al reposo. Cualquier estado puede pasar a falla.
+// Las transiciones a un mismo estado son siempre válidas y no notifican
+var transitions = map[TurnstileState][]TurnstileState{
+	StateIdle:       {StateGranted, StateAlwaysOpen, StateForbidden, StateClosing, StateFault},
+	StateGranted:    {StatePassing, StateClosing, StateIdle, StateAlwaysOpen, StateForbidden, StateFault},
+	StatePassing:    {StateClosing, StateIdle, StateGranted, StateAlwaysOpen, StateForbidden, StateFault},
+	StateClosing:    {StateIdle, StateGranted, StateAlwaysOpen, StateForbidden, StateFault},
+	StateAlwaysOpen: {StatePassing, StateClosing, StateIdle, StateGranted, StateForbidden, StateFault},
+	StateForbidden:  {StateIdle, StateGranted, StateAlwaysOpen, StateClosing, StateFault},
+	StateFault:      {StateIdle},
+}
+
+// TransitionHook se invoca después de cada cambio de estado
+type TransitionHook func(from, to TurnstileState)
+
+// TransitionEvent describe un cambio de estado junto con el equipo que lo
+// produjo
+type TransitionEvent struct {
+	From        TurnstileState
+	To          TurnstileState
+	Fingerprint string // ID de la huella del equipo (vacío si el cambio vino de Transition)
+}
+
+// TransitionEventHook se invoca después de cada cambio de estado con el
+// evento completo
+type TransitionEventHook func(TransitionEvent)
+
+// StateMachine mantiene el estado del torniquete y valida sus transiciones.
+// Es seguro para uso concurrente
+type StateMachine struct {
+	mu    sync.Mutex
+	state TurnstileState
+	hooks []TransitionEventHook
+}
+
+// NewStateMachine crea una máquina de estados en StateIdle
+func NewStateMachine() *StateMachine {
+	return &StateMachine{state: StateIdle}
+}
+
+// State retorna el estado actual
+func (m *StateMachine) State() TurnstileState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// CanTransition indica si la transición desde el estado actual es válida
+func (m *StateMachine) CanTransition(to TurnstileState) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return validTransition(m.state, to)
+}
+
+// Transition cambia al estado indicado si la transición es válida; si no,
+// retorna un error que envuelve ErrInvalidTransition
+func (m *StateMachine) Transition(to TurnstileState) error {
+	change, err := m.transition(to)
+	change.notify()
+	return err
+}
+
+// stateChange es un cambio de estado pendiente de notificar a los hooks
+// registrados en el momento del cambio
+type stateChange struct {
+	event TransitionEvent
+	hooks []TransitionEventHook
+}
+
+func (c stateChange) notify() {
+	for _, hook := range c.hooks {
+		hook(c.event)
+	}
+}
+
+// transition cambia el estado sin invocar los hooks; el cambio retornado
+// se notifica después con notify
+func (m *StateMachine) transition(to TurnstileState) (stateChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from := m.state
+	if !validTransition(from, to) {
+		return stateChange{}, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+	if from == to {
+		return stateChange{}, nil
+	}
+	m.state = to
+	return stateChange{
+		event: TransitionEvent{From: from, To: to},
+		hooks: append([]TransitionEventHook(nil), m.hooks...),
+	}, nil
+}
+
+// OnTransition registra una función que se invoca después de cada cambio
+// de estado, fuera del bloqueo interno. Los cambios detectados por el
+// dispositivo se notifican al terminar el comando, con el bus ya liberado,
+// desde la goroutine que lo envió: el hook puede enviar comandos, pero
+// mientras corre retrasa el retorno de ese comando, así que no debe
+// bloquear. Con comandos concurrentes no hay orden garantizado entre sus
+// notificaciones
+func (m *StateMachine) OnTransition(hook TransitionHook) {
+	if hook == nil {
+		return
+	}
+	m.OnTransitionEvent(func(e TransitionEvent) { hook(e.From, e.To) })
+}
+
+// OnTransitionEvent es como OnTransition, pero el hook recibe el evento
+// completo, con la huella del equipo que produjo el cambio (ver
+// Fingerprint)
+func (m *StateMachine) OnTransitionEvent(hook TransitionEventHook) {
+	if hook == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+func validTransition(from, to TurnstileState) bool {
+	if from == to {
+		return true
+	}
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// commandStates relaciona cada comando de control confirmado por el
+// equipo con el estado al que lleva
+var commandStates = map[protocol.CommandType]TurnstileState{
+	protocol.CmdLeftOpen:                   StateGranted,
+	protocol.CmdRightOpen:                  StateGranted,
+	protocol.CmdLeftAlwaysOpen:             StateAlwaysOpen,
+	protocol.CmdRightAlwaysOpen:            StateAlwaysOpen,
+	protocol.CmdCloseGate:                  StateClosing,
+	protocol.CmdForbiddenLeftPassage:       StateForbidden,
+	protocol.CmdForbiddenRightPassage:      StateForbidden,
+	protocol.CmdDisablePassageRestrictions: StateIdle,
+	protocol.CmdRestartDevice:              StateIdle,
+}
+
+// StateMachine retorna la máquina de estados del dispositivo. Se actualiza
+// con cada respuesta: las fallas reportadas llevan a StateFault, los
+// comandos de control confirmados a su estado, y una puerta cerrada termina
+// un cierre en curso. Un paso autorizado, en curso o siempre abierto vuelve
+// a StateIdle cuando la puerta, tras verse abierta, se reporta cerrada (por
+// ejemplo, el cierre automático después de un LeftOpen). StatePassing no
+// es observable en el protocolo documentado; lo declara el módulo que
+// detecta el paso
+func (d *Device) StateMachine() *StateMachine {
+	return d.state
+}
+
+// trackState actualiza la máquina de estados con la respuesta a cmd. Los
+// cambios quedan en d.changes hasta que takeChanges los retira; se
+// notifican con el bus liberado para que un hook pueda enviar comandos
+func (d *Device) trackState(cmd protocol.CommandType, response *protocol.Response) {
+	gate := d.gateState(response.FaultEvent, response.GateStatus)
+	fingerprint := fingerprintID(response.MachineNumber, response.VersionNumber)
+
+	var errs []error
+	transition := func(to TurnstileState) {
+		change, err := d.state.transition(to)
+		if change.event.To != change.event.From {
+			change.event.Fingerprint = fingerprint
+			d.changes = append(d.changes, change)
+		}
+		errs = append(errs, err)
+	}
+
+	switch current := d.state.State(); {
+	case gate == GateStateFault:
+		transition(StateFault)
+	case current == StateFault:
+		// El equipo ya no reporta la falla
+		transition(StateIdle)
+	}
+
+	if to, ok := commandStates[cmd]; ok {
+		transition(to)
+		d.gateOpened = gate != GateStateClosed
+	} else if gate == GateStateClosed {
+		switch d.state.State() {
+		case StateClosing:
+			transition(StateIdle)
+		case StateGranted, StatePassing, StateAlwaysOpen:
+			// La puerta se abrió y volvió a cerrarse por sí misma
+			if d.gateOpened {
+				transition(StateIdle)
+			}
+		}
+		d.gateOpened = false
+	} else {
+		d.gateOpened = true
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		d.logger.Debug("State not updated", "command", cmd, "error", err)
+	}
+}
+
+// takeChanges retira los cambios de estado pendientes. El bus debe estar
+// reservado
+func (d *Device) takeChanges() []stateChange {
+	changes := d.changes
+	d.changes = nil
+	return changes
+}
+
+// notifyChanges invoca los hooks de los cambios retirados con takeChanges
+func notifyChanges(changes []stateChange) {
+	for _, change := range changes {
+		change.notify()
+	}
+}
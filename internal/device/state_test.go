@@ -0,0 +1,113 @@
+package device
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// gateReplies responde a cada comando con el siguiente GateStatus de la lista
+func gateReplies(statuses ...byte) *rs485.ScriptedPort {
+	port := rs485.NewScriptedPort()
+	port.OnWrite = func([]byte) [][]byte {
+		frame := responseFrame(0, 0)
+		if len(statuses) > 0 {
+			frame[4] = statuses[0]
+			statuses = statuses[1:]
+			setChecksum(frame)
+		}
+		return [][]byte{frame}
+	}
+	return port
+}
+
+func TestStateReturnsToIdleAfterAutoClose(t *testing.T) {
+	const closed, open = 0x00, 0x01
+
+	tests := []struct {
+		name  string
+		open  func(context.Context, *Device) error
+		polls []byte
+		want  []TurnstileState
+	}{
+		{
+			name:  "granted closes after opening",
+			open:  func(ctx context.Context, d *Device) error { _, err := d.LeftOpen(ctx, 1); return err },
+			polls: []byte{closed, open, open, closed, closed},
+			want:  []TurnstileState{StateGranted, StateGranted, StateGranted, StateIdle, StateIdle},
+		},
+		{
+			name:  "always open closes after opening",
+			open:  func(ctx context.Context, d *Device) error { return d.LeftAlwaysOpen(ctx) },
+			polls: []byte{open, closed},
+			want:  []TurnstileState{StateAlwaysOpen, StateIdle},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			d := newTestDevice(t, gateReplies(append([]byte{closed}, tt.polls...)...))
+
+			if err := tt.open(ctx, d); err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			for i, want := range tt.want {
+				if _, err := d.GetStatus(ctx); err != nil {
+					t.Fatalf("GetStatus %d: %v", i, err)
+				}
+				if got := d.StateMachine().State(); got != want {
+					t.Errorf("after poll %d (gate 0x%02X) state = %v, want %v", i, tt.polls[i], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTransitionHookCanSendCommands(t *testing.T) {
+	const closed, open = 0x00, 0x01
+	ctx := context.Background()
+	d := newTestDevice(t, gateReplies(closed, open, closed, closed))
+
+	// Al volver a reposo, el hook consulta el estado del equipo
+	polled := make(chan error, 1)
+	d.StateMachine().OnTransition(func(from, to TurnstileState) {
+		if to == StateIdle {
+			_, err := d.GetStatus(ctx)
+			polled <- err
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		if _, err := d.LeftOpen(ctx, 1); err != nil {
+			done <- err
+			return
+		}
+		if _, err := d.GetStatus(ctx); err != nil {
+			done <- err
+			return
+		}
+		_, err := d.GetStatus(ctx)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("command: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("deadlock: hook could not send a command")
+	}
+	select {
+	case err := <-polled:
+		if err != nil {
+			t.Errorf("GetStatus from hook: %v", err)
+		}
+	default:
+		t.Fatal("hook did not run")
+	}
+}
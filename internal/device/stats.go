@@ -0,0 +1,82 @@
+package device
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats contiene contadores de la comunicación con el dispositivo
+type Stats struct {
+	Commands   uint64 // Comandos enviados (sin contar reintentos)
+	Failures   uint64 // Comandos que fallaron tras agotar los reintentos
+	Retries    uint64 // Reintentos realizados
+	Timeouts   uint64 // Lecturas sin ningún dato recibido
+	Collisions uint64 // Errores de trama atribuibles a colisiones en el bus
+	Busy       uint64 // Rechazos por dispositivo ocupado (ver WithBusyPolicy)
+
+	// Espera por el bus desde que se pide hasta que se obtiene, para los
+	// comandos de control y para la telemetría (GetStatus), y consultas
+	// cortadas para dar paso al control (ver WithTelemetryBudget)
+	ControlWaitAvg   time.Duration
+	ControlWaitMax   time.Duration
+	TelemetryWaitAvg time.Duration
+	TelemetryWaitMax time.Duration
+	Preemptions      uint64
+
+	// Reinicios inesperados del equipo (contadores que bajan sin un reset
+	// propio ni desborde, o cambio de versión) y el instante del último. Un
+	// reset de contadores hecho por otro proceso también cuenta. Los
+	// reinicios pedidos con el comando Reset van en CommandedRestarts
+	RestartCount      uint64
+	LastRestart       time.Time
+	CommandedRestarts uint64
+
+	// Huella del equipo según la última respuesta (vacía si aún no hay
+	// respuestas) y particularidades observadas desde la apertura, para
+	// agrupar métricas por firmware (ver Fingerprint)
+	Fingerprint string
+	Quirks      []string
+}
+
+// stats mantiene los contadores de forma concurrente
+type stats struct {
+	commands   atomic.Uint64
+	failures   atomic.Uint64
+	retries    atomic.Uint64
+	timeouts   atomic.Uint64
+	collisions atomic.Uint64
+	busy       atomic.Uint64
+	restarts   restartTracker
+}
+
+// Stats retorna una copia de los contadores de comunicación
+func (d *Device) Stats() Stats {
+	restarts, lastRestart, commanded := d.stats.restarts.snapshot()
+	controlAvg, controlMax := d.sched.waits[trafficControl].snapshot()
+	telemetryAvg, telemetryMax := d.sched.waits[trafficTelemetry].snapshot()
+	var fingerprint string
+	if version, ok := d.stats.restarts.firmware(); ok {
+		fingerprint = fingerprintID(d.config.DeviceID, version)
+	}
+	return Stats{
+		Commands:   d.stats.commands.Load(),
+		Failures:   d.stats.failures.Load(),
+		Retries:    d.stats.retries.Load(),
+		Timeouts:   d.stats.timeouts.Load(),
+		Collisions: d.stats.collisions.Load(),
+		Busy:       d.stats.busy.Load(),
+
+		RestartCount:      restarts,
+		LastRestart:       lastRestart,
+		CommandedRestarts: commanded,
+
+		ControlWaitAvg:   controlAvg,
+		ControlWaitMax:   controlMax,
+		TelemetryWaitAvg: telemetryAvg,
+		TelemetryWaitMax: telemetryMax,
+		Preemptions:      d.sched.preemptions.Load(),
+
+		Fingerprint: fingerprint,
+		Quirks:      d.quirks.names(),
+	}
+}
@@ -0,0 +1,69 @@
+package device
+
+import (
+	"time"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+const (
+	// deviceProcessingMargin es el tiempo estimado que el controlador tarda
+	// en procesar un comando antes de empezar a transmitir la respuesta
+	deviceProcessingMargin = 150 * time.Millisecond
+
+	// minReadTimeout evita deadlines demasiado agresivos en enlaces rápidos
+	minReadTimeout = 200 * time.Millisecond
+
+	// minWriteTimeout es el mínimo para la escritura de una trama
+	minWriteTimeout = 100 * time.Millisecond
+)
+
+// CharTime retorna el tiempo de transmisión de un carácter serial
+// (bit de inicio + bits de datos + paridad + bits de parada)
+func CharTime(baudRate, dataBits, stopBits int, parity string) time.Duration {
+	if baudRate <= 0 {
+		return 0
+	}
+
+	bits := 1 + dataBits + stopBits
+	if parity != "" && parity != "none" {
+		bits++
+	}
+
+	return time.Duration(bits) * time.Second / time.Duration(baudRate)
+}
+
+// FrameTime retorna el tiempo de transmisión de n bytes con la configuración dada
+func FrameTime(config *Config, n int) time.Duration {
+	return time.Duration(n) * CharTime(config.BaudRate, config.DataBits, config.StopBits, config.Parity)
+}
+
+// AdaptiveReadTimeout calcula el deadline de lectura de una respuesta: el
+// tiempo de enviar el comando y recibir la respuesta (con holgura x2) más
+// el margen de procesamiento del dispositivo
+func AdaptiveReadTimeout(config *Config) time.Duration {
+	timeout := 2*FrameTime(config, protocol.FrameSize+protocol.ResponseSize) + deviceProcessingMargin
+	if timeout < minReadTimeout {
+		return minReadTimeout
+	}
+	return timeout
+}
+
+// AdaptiveWriteTimeout calcula el timeout de escritura de un comando
+func AdaptiveWriteTimeout(config *Config) time.Duration {
+	timeout := 2 * FrameTime(config, protocol.FrameSize)
+	if timeout < minWriteTimeout {
+		return minWriteTimeout
+	}
+	return timeout
+}
+
+// applyTimingDefaults completa los timeouts no configurados a partir del baudrate
+func applyTimingDefaults(config *Config) {
+	if config.ReadTimeout <= 0 {
+		config.ReadTimeout = AdaptiveReadTimeout(config)
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = AdaptiveWriteTimeout(config)
+	}
+}
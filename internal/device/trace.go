@@ -0,0 +1,61 @@
+package device
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// tracer escribe una línea por transacción, independiente del nivel de log
+type tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithTraceWriter escribe en w una línea compacta por transacción (hora,
+// comando, resultado, latencia, reintentos y los Metadata del contexto),
+// sin importar el nivel de log. Pensado como registro permanente tipo "caja
+// negra" en producción:
+//
+//	2026-01-02T15:04:05.000Z cmd=LeftOpen result=ok latency=12ms elapsed=12ms retries=0 operator=op-7 transaction=T-991
+//	2026-01-02T15:04:06.000Z cmd=GetStatus result=error elapsed=1.2s retries=3 error="..."
+//
+// Los errores de escritura en w se ignoran para no afectar los comandos
+func WithTraceWriter(w io.Writer) Option {
+	return func(d *Device) {
+		if w != nil {
+			d.tracer = &tracer{w: w}
+		}
+	}
+}
+
+// trace registra una transacción terminada
+func (t *tracer) trace(start time.Time, elapsed time.Duration, cmd protocol.CommandType, response *protocol.Response, retries uint64, md Metadata, err error) {
+	line := fmt.Sprintf("%s cmd=%s", start.UTC().Format("2006-01-02T15:04:05.000Z07:00"), cmd)
+	if err != nil {
+		line += fmt.Sprintf(" result=error elapsed=%v retries=%d error=%s", elapsed, retries, strconv.Quote(err.Error()))
+	} else {
+		line += fmt.Sprintf(" result=ok latency=%v elapsed=%v retries=%d", response.Latency, elapsed, retries)
+	}
+	args := md.logArgs()
+	for i := 0; i+1 < len(args); i += 2 {
+		line += fmt.Sprintf(" %s=%s", args[i], traceValue(args[i+1].(string)))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.w, line+"\n")
+}
+
+// traceValue entrecomilla los valores que romperían el formato clave=valor
+func traceValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		return strconv.Quote(v)
+	}
+	return v
+}
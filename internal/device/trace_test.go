@@ -0,0 +1,38 @@
+package device
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+func TestTraceIncludesMetadata(t *testing.T) {
+	var out bytes.Buffer
+	d := newTestDevice(t, rs485.NewScriptedPort(responseFrame(0, 0), responseFrame(0, 0)), WithTraceWriter(&out))
+
+	ctx := ContextWithMetadata(context.Background(), Metadata{
+		OperatorID:    "op-7",
+		TransactionID: "T-991",
+		Labels:        map[string]string{"station": "Calle 45", "lane": "2"},
+	})
+	if _, err := d.GetStatus(ctx); err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if _, err := d.GetStatus(context.Background()); err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("trace has %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if want := ` retries=0 operator=op-7 transaction=T-991 lane=2 station="Calle 45"`; !strings.HasSuffix(lines[0], want) {
+		t.Errorf("trace line = %q, want suffix %q", lines[0], want)
+	}
+	if !strings.HasSuffix(lines[1], " retries=0") {
+		t.Errorf("trace line without metadata = %q, want it to end at retries", lines[1])
+	}
+}
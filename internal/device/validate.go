@@ -0,0 +1,81 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// FieldError describe un problema en un campo de la configuración
+type FieldError struct {
+	Field string      // Nombre del campo de Config
+	Value interface{} // Valor recibido
+	Msg   string      // Descripción del problema
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s (got %v)", e.Field, e.Msg, e.Value)
+}
+
+// ConfigError agrupa todos los problemas encontrados en una configuración.
+// errors.Is(err, ErrInvalidConfig) es verdadero y errors.As permite obtener
+// cada *FieldError
+type ConfigError struct {
+	Port   string        // Puerto de la configuración, para identificarla entre varias
+	Fields []*FieldError // Problemas encontrados, en orden de campo
+}
+
+func (e *ConfigError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("%v for port %q: %s", ErrInvalidConfig, e.Port, strings.Join(msgs, "; "))
+}
+
+// Unwrap permite usar errors.Is con ErrInvalidConfig y errors.As con FieldError
+func (e *ConfigError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields)+1)
+	errs = append(errs, ErrInvalidConfig)
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// Validate verifica la configuración completa y reporta todos los problemas
+// a la vez como *ConfigError. Los campos opcionales en cero usan su valor
+// por defecto y se consideran válidos
+func (c *Config) Validate() error {
+	if c == nil {
+		return fmt.Errorf("%w: nil config", ErrInvalidConfig)
+	}
+
+	var fields []*FieldError
+	check := func(ok bool, field string, value interface{}, msg string) {
+		if !ok {
+			fields = append(fields, &FieldError{Field: field, Value: value, Msg: msg})
+		}
+	}
+
+	check(c.Port != "", "Port", c.Port, "port cannot be empty")
+	check(c.BaudRate > 0, "BaudRate", c.BaudRate, "baud rate must be positive")
+	check(c.DataBits >= 5 && c.DataBits <= 8, "DataBits", c.DataBits, "data bits must be between 5 and 8")
+	check(c.StopBits >= 1 && c.StopBits <= 2, "StopBits", c.StopBits, "stop bits must be 1 or 2")
+	check(c.Parity == "none" || c.Parity == "odd" || c.Parity == "even", "Parity", c.Parity, "parity must be 'none', 'odd', or 'even'")
+	check(c.Timeout > 0, "Timeout", c.Timeout, "timeout must be positive")
+	check(c.ReadTimeout >= 0, "ReadTimeout", c.ReadTimeout, "read timeout cannot be negative")
+	check(c.WriteTimeout >= 0, "WriteTimeout", c.WriteTimeout, "write timeout cannot be negative")
+	check(c.RetryCount >= 0, "RetryCount", c.RetryCount, "retry count cannot be negative")
+	check(c.ReadChunkSize == 0 || (c.ReadChunkSize >= MinReadChunkSize && c.ReadChunkSize <= MaxReadChunkSize),
+		"ReadChunkSize", c.ReadChunkSize, fmt.Sprintf("read chunk size must be between %d and %d", MinReadChunkSize, MaxReadChunkSize))
+	check(c.MaxReadBuffer == 0 || (c.MaxReadBuffer >= MinMaxReadBuffer && c.MaxReadBuffer <= MaxMaxReadBuffer),
+		"MaxReadBuffer", c.MaxReadBuffer, fmt.Sprintf("max read buffer must be between %d and %d", MinMaxReadBuffer, MaxMaxReadBuffer))
+
+	if len(fields) > 0 {
+		return &ConfigError{Port: c.Port, Fields: fields}
+	}
+	return nil
+}
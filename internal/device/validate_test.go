@@ -0,0 +1,130 @@
+package device
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dumacp/ds205a/internal/rs485"
+)
+
+// validConfig retorna una configuración completa y válida
+func validConfig() *Config {
+	return &Config{
+		Port:     "/dev/ttyTEST",
+		BaudRate: 9600,
+		DataBits: 8,
+		StopBits: 1,
+		Parity:   "none",
+		Timeout:  time.Second,
+		DeviceID: testDeviceID,
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		fields []string
+	}{
+		{name: "valid", modify: func(*Config) {}},
+		{name: "zero buffers use defaults", modify: func(c *Config) { c.ReadChunkSize, c.MaxReadBuffer = 0, 0 }},
+		{name: "empty port", modify: func(c *Config) { c.Port = "" }, fields: []string{"Port"}},
+		{name: "baud rate", modify: func(c *Config) { c.BaudRate = 0 }, fields: []string{"BaudRate"}},
+		{name: "data bits", modify: func(c *Config) { c.DataBits = 9 }, fields: []string{"DataBits"}},
+		{name: "stop bits", modify: func(c *Config) { c.StopBits = 3 }, fields: []string{"StopBits"}},
+		{name: "parity", modify: func(c *Config) { c.Parity = "mark" }, fields: []string{"Parity"}},
+		{name: "timeout", modify: func(c *Config) { c.Timeout = 0 }, fields: []string{"Timeout"}},
+		{name: "negative read timeout", modify: func(c *Config) { c.ReadTimeout = -1 }, fields: []string{"ReadTimeout"}},
+		{name: "negative write timeout", modify: func(c *Config) { c.WriteTimeout = -1 }, fields: []string{"WriteTimeout"}},
+		{name: "negative retries", modify: func(c *Config) { c.RetryCount = -1 }, fields: []string{"RetryCount"}},
+		{name: "chunk too large", modify: func(c *Config) { c.ReadChunkSize = MaxReadChunkSize + 1 }, fields: []string{"ReadChunkSize"}},
+		{name: "buffer too small", modify: func(c *Config) { c.MaxReadBuffer = MinMaxReadBuffer - 1 }, fields: []string{"MaxReadBuffer"}},
+		{
+			name:   "all problems at once in field order",
+			modify: func(c *Config) { c.Port, c.Parity, c.MaxReadBuffer = "", "mark", -1 },
+			fields: []string{"Port", "Parity", "MaxReadBuffer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			tt.modify(config)
+
+			err := config.Validate()
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("Validate = %v, want ErrInvalidConfig", err)
+			}
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Validate = %T, want *ConfigError", err)
+			}
+			var got []string
+			for _, f := range configErr.Fields {
+				got = append(got, f.Field)
+			}
+			if !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("fields = %v, want %v", got, tt.fields)
+			}
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) || fieldErr.Field != tt.fields[0] {
+				t.Errorf("errors.As(*FieldError) = %v, want field %s", fieldErr, tt.fields[0])
+			}
+		})
+	}
+}
+
+func TestFieldErrorMessage(t *testing.T) {
+	err := &FieldError{Field: "BaudRate", Value: 0, Msg: "baud rate must be positive"}
+	if got, want := err.Error(), "BaudRate: baud rate must be positive (got 0)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestWithReadBufferRejectsOutOfRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunk     int
+		maxBuffer int
+		field     string
+		wantChunk int
+		wantMax   int
+	}{
+		{name: "defaults", wantChunk: DefaultReadChunkSize, wantMax: DefaultMaxReadBuffer},
+		{name: "in range", chunk: 64, maxBuffer: 1024, wantChunk: 64, wantMax: 1024},
+		{name: "chunk too large", chunk: MaxReadChunkSize + 1, field: "ReadChunkSize"},
+		{name: "negative chunk", chunk: -1, field: "ReadChunkSize"},
+		{name: "buffer too small", maxBuffer: MinMaxReadBuffer - 1, field: "MaxReadBuffer"},
+		{name: "buffer too large", maxBuffer: MaxMaxReadBuffer + 1, field: "MaxReadBuffer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := New(validConfig(), WithSerialPort(rs485.NewScriptedPort()), WithReadBuffer(tt.chunk, tt.maxBuffer))
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("New: %v", err)
+				}
+				if d.config.ReadChunkSize != tt.wantChunk || d.config.MaxReadBuffer != tt.wantMax {
+					t.Errorf("buffers = %d/%d, want %d/%d",
+						d.config.ReadChunkSize, d.config.MaxReadBuffer, tt.wantChunk, tt.wantMax)
+				}
+				return
+			}
+
+			var fieldErr *FieldError
+			if !errors.Is(err, ErrInvalidConfig) || !errors.As(err, &fieldErr) || fieldErr.Field != tt.field {
+				t.Errorf("New = %v, want a %s field error", err, tt.field)
+			}
+		})
+	}
+}
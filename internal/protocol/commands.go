@@ -1,7 +1,18 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
+	"time"
+)
+
+var (
+	ErrFrameTooShort     = errors.New("response frame too small")
+	ErrInvalidHeader     = errors.New("invalid response header")
+	ErrMachineIDMismatch = errors.New("machine ID mismatch")
+	ErrCommandExecution  = errors.New("command execution failed")
+	ErrDataTooLarge      = errors.New("data too large")
+	ErrChecksumMismatch  = errors.New("checksum mismatch")
 )
 
 // CommandType representa los tipos de comandos disponibles
@@ -56,9 +67,15 @@ type Response struct {
 	InfraredStatus       byte    // Infrared Status
 	CommandExecution     byte    // Command Execution (0x55 = success)
 	PowerSupplyVoltage   byte    // Power Supply Voltage
-	Undefined1           byte    // Undefined
-	Undefined2           byte    // Undefined
 	Checksum             byte    // Checksum
+
+	Revision string             // Revisión del protocolo según VersionNumber (ver RevisionFor)
+	reserved [ReservedSize]byte // Bytes sin documentar (ver Reserved y ReservedField)
+
+	// Metadatos de recepción (no forman parte de la trama)
+	ReceivedAt time.Time     // Instante de recepción de la trama completa
+	Latency    time.Duration // Tiempo desde el envío del comando
+	Attempts   int           // Intentos usados para obtenerla (1 = sin reintentos)
 }
 
 // GetLeftCount convierte los 3 bytes del contador izquierdo a uint32
@@ -75,11 +92,23 @@ func (r *Response) GetRightCount() uint32 {
 		uint32(r.RightPedestrianCount[2])
 }
 
+// Reserved retorna una copia de los bytes de la respuesta que el fabricante
+// aún no documenta (posiciones ReservedOffset..ReservedOffset+ReservedSize-1),
+// sin interpretar. Los campos que una revisión posterior documente se leen
+// por nombre con ReservedField
+func (r *Response) Reserved() []byte {
+	return append([]byte(nil), r.reserved[:]...)
+}
+
 // IsSuccess verifica si la respuesta indica éxito
 func (r *Response) IsSuccess() bool {
 	return r.CommandExecution == byte(RespSuccess)
 }
 
+// Revision identifica el dialecto del protocolo implementado: tramas de
+// comando de 8 bytes, respuestas de 18 bytes y checksum TX/RX según doc/
+const Revision = "ds205a-rs485/1"
+
 // Protocol constants según CSV
 const (
 	FrameHeader      = 0x7E // Starting Position para comandos
@@ -90,33 +119,55 @@ const (
 	DataSize         = 3    // 3 bytes de datos (Data 0, Data 1, Data 2)
 	RestartParam     = 0x60 // Parámetro requerido para restart
 	SuccessExecution = 0x55 // Command Execution value para éxito
+	ReservedOffset   = 15   // Primer byte sin documentar de la respuesta
+	ReservedSize     = 2    // Bytes sin documentar de la respuesta
 )
 
-// calculateTxChecksum implementa el algoritmo TX del documento
-// Suma todos los bytes y aplica NOT (~ret)
-func CalculateTxChecksum(data []byte) byte {
+// ChecksumTx implementa el algoritmo TX del documento: suma (módulo 256)
+// todos los bytes de frame y aplica NOT. frame son los bytes cubiertos por
+// el checksum, es decir la trama sin su último byte; para un comando:
+//
+//	cmd[FrameSize-1] = ChecksumTx(cmd[:FrameSize-1])
+func ChecksumTx(frame []byte) byte {
 	var ret byte = 0
-	for i := 0; i < len(data); i++ {
-		ret += data[i]
+	for i := 0; i < len(frame); i++ {
+		ret += frame[i]
 	}
 	ret = ^ret // NOT operation
 	return ret
 }
 
-// validateRxChecksum implementa el algoritmo RX del documento
-// Suma todos los bytes + 1, debe ser 0 si los datos son correctos
-func ValidateRxChecksum(data []byte) bool {
+// VerifyRx implementa el algoritmo RX del documento sobre una respuesta
+// completa (header incluido; solo se consideran sus primeros ResponseSize
+// bytes): la suma de los bytes 1..17, checksum incluido, más uno debe dar
+// cero. Retorna ErrFrameTooShort, ErrInvalidHeader o ErrChecksumMismatch
+// (con el valor recibido y el esperado).
+//
+// Algunos firmwares no siguen este algoritmo (ver la particularidad
+// rx-checksum-nonstandard), por eso ParseResponse no lo exige
+func VerifyRx(frame []byte) error {
+	if len(frame) < ResponseSize {
+		return fmt.Errorf("%w: %d bytes (expected %d)", ErrFrameTooShort, len(frame), ResponseSize)
+	}
+	if frame[0] != ResponseHeader {
+		return fmt.Errorf("%w: 0x%02X (expected 0x%02X)", ErrInvalidHeader, frame[0], ResponseHeader)
+	}
+
 	var ret byte = 0
-	for i := 0; i < len(data); i++ {
-		ret += data[i]
+	for _, b := range frame[1:ResponseSize] {
+		ret += b
+	}
+	if ret+1 != 0 {
+		got := frame[ResponseSize-1]
+		return fmt.Errorf("%w: 0x%02X (expected 0x%02X)", ErrChecksumMismatch, got, got-(ret+1))
 	}
-	return (ret + 1) == 0
+	return nil
 }
 
 // BuildCommand construye un frame de comando según especificación CSV
 func BuildCommand(deviceID byte, cmd CommandType, data []byte) ([]byte, error) {
 	if len(data) > DataSize {
-		return nil, fmt.Errorf("data too large: %d bytes (max %d)", len(data), DataSize)
+		return nil, fmt.Errorf("%w: %d bytes (max %d)", ErrDataTooLarge, len(data), DataSize)
 	}
 
 	// Frame structure: [Header][Undefined][MachineNumber][Command][Data0][Data1][Data2][Checksum]
@@ -137,7 +188,7 @@ func BuildCommand(deviceID byte, cmd CommandType, data []byte) ([]byte, error) {
 	}
 
 	// Calculate checksum using algorithm from doc (exclude header and checksum position)
-	checksum := CalculateTxChecksum(frame[0:])
+	checksum := ChecksumTx(frame[0:])
 	frame = append(frame, checksum)
 
 	return frame, nil
@@ -146,16 +197,16 @@ func BuildCommand(deviceID byte, cmd CommandType, data []byte) ([]byte, error) {
 // ParseResponse parsea una respuesta del dispositivo según reponse.csv
 func ParseResponse(data []byte, expectedMachineID byte) (*Response, error) {
 	if len(data) < ResponseSize {
-		return nil, fmt.Errorf("response frame too small: %d bytes (expected %d)", len(data), ResponseSize)
+		return nil, fmt.Errorf("%w: %d bytes (expected %d)", ErrFrameTooShort, len(data), ResponseSize)
 	}
 
 	// Verificar header de respuesta
 	if data[0] != ResponseHeader {
-		return nil, fmt.Errorf("invalid response header: 0x%02X (expected 0x%02X)", data[0], ResponseHeader)
+		return nil, fmt.Errorf("%w: 0x%02X (expected 0x%02X)", ErrInvalidHeader, data[0], ResponseHeader)
 	}
 
 	// // Verificar checksum usando algoritmo RX (todos los bytes excepto el primer header)
-	// if !ValidateRxChecksum(data[1:]) {
+	// if err := VerifyRx(data); err != nil {
 	// 	return nil, fmt.Errorf("checksum validation failed")
 	// }
 
@@ -170,25 +221,28 @@ func ParseResponse(data []byte, expectedMachineID byte) (*Response, error) {
 		InfraredStatus:     data[12], // Infrared Status (posición 12)
 		CommandExecution:   data[13], // Command Execution (posición 13)
 		PowerSupplyVoltage: data[14], // Power Supply Voltage (posición 14)
-		Undefined1:         data[15], // Placeholder para mantener compatibilidad
-		Undefined2:         data[16], // Placeholder para mantener compatibilidad
 		Checksum:           data[17], // Checksum (último byte del frame de 18)
 	}
 
+	// Bytes sin documentar (posiciones 15-16), interpretados según la
+	// revisión que habla el firmware
+	copy(response.reserved[:], data[ReservedOffset:ReservedOffset+ReservedSize])
+	response.Revision = RevisionFor(response.VersionNumber)
+
 	// Extraer contadores de 3 bytes cada uno (6 bytes contiguos: posiciones 6-11)
 	copy(response.LeftPedestrianCount[:], data[6:9])   // Bytes 6,7,8
 	copy(response.RightPedestrianCount[:], data[9:12]) // Bytes 9,10,11
 
 	// Verificar que el Machine Number coincida
 	if response.MachineNumber != expectedMachineID {
-		return nil, fmt.Errorf("machine ID mismatch: got 0x%02X, expected 0x%02X",
-			response.MachineNumber, expectedMachineID)
+		return nil, fmt.Errorf("%w: got 0x%02X, expected 0x%02X",
+			ErrMachineIDMismatch, response.MachineNumber, expectedMachineID)
 	}
 
 	// Verificar que el comando se ejecutó exitosamente
 	if response.CommandExecution != SuccessExecution {
-		return nil, fmt.Errorf("command execution failed: 0x%02X (expected 0x%02X)",
-			response.CommandExecution, SuccessExecution)
+		return nil, fmt.Errorf("%w: 0x%02X (expected 0x%02X)",
+			ErrCommandExecution, response.CommandExecution, SuccessExecution)
 	}
 
 	return response, nil
@@ -0,0 +1,234 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// FrameKind indica el tipo de trama según su byte de inicio
+type FrameKind int
+
+const (
+	FrameKindUnknown  FrameKind = iota // Trama no reconocida
+	FrameKindCommand                   // Trama de comando (0x7E)
+	FrameKindResponse                  // Trama de respuesta (0x7F)
+)
+
+func (k FrameKind) String() string {
+	switch k {
+	case FrameKindCommand:
+		return "command"
+	case FrameKindResponse:
+		return "response"
+	default:
+		return "unknown"
+	}
+}
+
+// Field describe un campo decodificado de una trama
+type Field struct {
+	Name   string // Nombre del campo según la documentación
+	Offset int    // Posición del primer byte
+	Raw    []byte // Bytes del campo
+	Value  string // Interpretación legible
+}
+
+// Check describe el resultado de una validación sobre la trama
+type Check struct {
+	Name   string // Validación realizada
+	OK     bool   // Resultado
+	Detail string // Detalle del resultado
+}
+
+// DecodedFrame contiene los campos y validaciones de una trama
+type DecodedFrame struct {
+	Kind   FrameKind
+	Data   []byte
+	Fields []Field
+	Checks []Check
+}
+
+// Valid indica si todas las validaciones fueron exitosas
+func (f *DecodedFrame) Valid() bool {
+	for _, c := range f.Checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodeFrame decodifica una trama de comando o respuesta sin requerir que
+// sea válida, reportando cada problema como una validación fallida. Está
+// pensado para analizar volcados hexadecimales fuera de línea
+func DecodeFrame(data []byte) *DecodedFrame {
+	frame := &DecodedFrame{Data: append([]byte(nil), data...)}
+	if len(data) == 0 {
+		frame.Checks = append(frame.Checks, Check{Name: "length", Detail: "empty frame"})
+		return frame
+	}
+
+	switch data[0] {
+	case FrameHeader:
+		frame.Kind = FrameKindCommand
+		decodeCommandFrame(frame)
+	case ResponseHeader:
+		frame.Kind = FrameKindResponse
+		decodeResponseFrame(frame)
+	default:
+		frame.Checks = append(frame.Checks, Check{
+			Name:   "header",
+			Detail: fmt.Sprintf("0x%02X is neither 0x%02X (command) nor 0x%02X (response)", data[0], FrameHeader, ResponseHeader),
+		})
+	}
+
+	return frame
+}
+
+// decodeCommandFrame decodifica una trama de comando (frame.csv)
+func decodeCommandFrame(frame *DecodedFrame) {
+	data := frame.Data
+	frame.Checks = append(frame.Checks, lengthCheck(len(data), FrameSize))
+
+	fields := []struct {
+		name   string
+		format func(b []byte) string
+	}{
+		{"Starting Position", hexValue},
+		{"Undefined", hexValue},
+		{"Machine Number", decValue},
+		{"Command Value", func(b []byte) string { return CommandType(b[0]).String() }},
+		{"Data 0", hexValue},
+		{"Data 1", hexValue},
+		{"Data 2", hexValue},
+		{"Checksum", hexValue},
+	}
+	for i, f := range fields {
+		if i >= len(data) {
+			break
+		}
+		frame.Fields = append(frame.Fields, Field{Name: f.name, Offset: i, Raw: data[i : i+1], Value: f.format(data[i : i+1])})
+	}
+
+	if len(data) >= FrameSize {
+		frame.Checks = append(frame.Checks, txChecksumCheck(data[:FrameSize]))
+	}
+}
+
+// responseField describe cómo mostrar un campo de la respuesta
+type responseField struct {
+	name   string
+	offset int
+	length int
+	format func(b []byte) string
+}
+
+// decodeResponseFrame decodifica una trama de respuesta (reponse.csv)
+func decodeResponseFrame(frame *DecodedFrame) {
+	data := frame.Data
+	frame.Checks = append(frame.Checks, lengthCheck(len(data), ResponseSize))
+
+	fields := []responseField{
+		{"Starting Position", 0, 1, hexValue},
+		{"Version Number", 1, 1, decValue},
+		{"Machine Number", 2, 1, decValue},
+		{"Fault Event", 3, 1, hexValue},
+		{"Gate Status", 4, 1, hexValue},
+		{"Alarm Event", 5, 1, hexValue},
+		{"Left Pedestrian Count", 6, 3, countValue},
+		{"Right Pedestrian Count", 9, 3, countValue},
+		{"Infrared Status", 12, 1, hexValue},
+		{"Command Execution", 13, 1, func(b []byte) string { return ResponseCode(b[0]).String() }},
+		{"Power Supply Voltage", 14, 1, decValue},
+	}
+	if len(data) > 1 {
+		// Los bytes reservados se nombran según la revisión del firmware
+		for _, f := range reservedFields(RevisionFor(data[1])) {
+			fields = append(fields, responseField{f.Name, f.Offset, f.Size, bytesValue})
+		}
+	}
+	fields = append(fields, responseField{"Checksum", 17, 1, hexValue})
+
+	for _, f := range fields {
+		if f.offset+f.length > len(data) {
+			break
+		}
+		raw := data[f.offset : f.offset+f.length]
+		frame.Fields = append(frame.Fields, Field{Name: f.name, Offset: f.offset, Raw: raw, Value: f.format(raw)})
+	}
+
+	if len(data) >= ResponseSize {
+		frame.Checks = append(frame.Checks, rxChecksumCheck(data[:ResponseSize]))
+		exec := data[13]
+		frame.Checks = append(frame.Checks, Check{
+			Name:   "execution",
+			OK:     exec == SuccessExecution,
+			Detail: fmt.Sprintf("0x%02X (%s)", exec, ResponseCode(exec)),
+		})
+	}
+}
+
+// reservedFields cubre los bytes reservados con los campos documentados en
+// revision; los tramos sin documentar quedan como "Reserved"
+func reservedFields(revision string) []ReservedField {
+	var fields []ReservedField
+	next := ReservedOffset
+	for _, f := range ReservedLayout(revision) {
+		if f.Offset > next {
+			fields = append(fields, ReservedField{Name: "Reserved", Offset: next, Size: f.Offset - next})
+		}
+		fields = append(fields, f)
+		next = f.Offset + f.Size
+	}
+	if end := ReservedOffset + ReservedSize; next < end {
+		fields = append(fields, ReservedField{Name: "Reserved", Offset: next, Size: end - next})
+	}
+	return fields
+}
+
+// lengthCheck valida el tamaño de la trama
+func lengthCheck(got, want int) Check {
+	return Check{
+		Name:   "length",
+		OK:     got == want,
+		Detail: fmt.Sprintf("%d bytes (expected %d)", got, want),
+	}
+}
+
+// txChecksumCheck valida el checksum TX (último byte) de una trama de comando
+func txChecksumCheck(data []byte) Check {
+	return checksumCheck(data[len(data)-1], ChecksumTx(data[:len(data)-1]))
+}
+
+// rxChecksumCheck valida el checksum RX de una respuesta completa con
+// VerifyRx, el mismo algoritmo que usa la librería
+func rxChecksumCheck(data []byte) Check {
+	got := data[ResponseSize-1]
+	if err := VerifyRx(data); err != nil {
+		// El valor que VerifyRx acepta es el NOT de la suma de los bytes 1..16
+		return checksumCheck(got, ChecksumTx(data[1:ResponseSize-1]))
+	}
+	return checksumCheck(got, got)
+}
+
+func checksumCheck(got, want byte) Check {
+	if got == want {
+		return Check{Name: "checksum", OK: true, Detail: fmt.Sprintf("0x%02X", got)}
+	}
+	return Check{Name: "checksum", Detail: fmt.Sprintf("0x%02X (expected 0x%02X)", got, want)}
+}
+
+func hexValue(b []byte) string {
+	return fmt.Sprintf("0x%02X", b[0])
+}
+
+func bytesValue(b []byte) string {
+	return fmt.Sprintf("% 02X", b)
+}
+
+func decValue(b []byte) string {
+	return fmt.Sprintf("%d", b[0])
+}
+
+func countValue(b []byte) string {
+	return fmt.Sprintf("%d", uint32(b[0])<<16|uint32(b[1])<<8|uint32(b[2]))
+}
@@ -0,0 +1,51 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func findCheck(t *testing.T, frame *DecodedFrame, name string) Check {
+	t.Helper()
+	for _, c := range frame.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, frame.Checks)
+	return Check{}
+}
+
+func TestDecodeChecksumMatchesLibrary(t *testing.T) {
+	response := []byte{0x7F, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x07, 0xF0, 0x55, 0x20, 0x00, 0x00, 0x8C}
+	command, err := BuildCommand(0x01, CmdGetStatus, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corrupt := append([]byte(nil), response...)
+	corrupt[ResponseSize-1] = 0x0D
+
+	tests := []struct {
+		name  string
+		frame []byte
+		want  bool
+	}{
+		{"response", response, true},
+		{"response with TX checksum", corrupt, false},
+		{"command", command, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := findCheck(t, DecodeFrame(tt.frame), "checksum")
+			if check.OK != tt.want {
+				t.Errorf("checksum check = %+v, want OK=%v", check, tt.want)
+			}
+			if tt.frame[0] == ResponseHeader {
+				if libraryOK := VerifyRx(tt.frame) == nil; libraryOK != check.OK {
+					t.Errorf("decoder OK=%v, VerifyRx OK=%v", check.OK, libraryOK)
+				}
+			}
+		})
+	}
+}
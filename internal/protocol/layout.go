@@ -0,0 +1,48 @@
+package protocol
+
+// ReservedField es un campo con nombre dentro de los bytes reservados de la
+// respuesta, documentado a partir de una revisión del protocolo
+type ReservedField struct {
+	Name   string // Nombre del campo según la documentación del fabricante
+	Offset int    // Posición del primer byte en la trama (desde ReservedOffset)
+	Size   int    // Bytes del campo
+}
+
+// reservedLayouts relaciona cada revisión del protocolo con los campos
+// documentados dentro de los bytes reservados. Revision no documenta
+// ninguno; cuando el fabricante lo haga, la nueva revisión agrega aquí su
+// tabla y los binarios existentes siguen leyendo esos bytes con Reserved
+var reservedLayouts = map[string][]ReservedField{
+	Revision: nil,
+}
+
+// firmwareRevisions relaciona versiones de firmware (Version Number) con la
+// revisión del protocolo que implementan. Las versiones que no aparecen
+// hablan Revision
+var firmwareRevisions = map[byte]string{}
+
+// RevisionFor retorna la revisión del protocolo de la versión de firmware
+func RevisionFor(version byte) string {
+	if revision, ok := firmwareRevisions[version]; ok {
+		return revision
+	}
+	return Revision
+}
+
+// ReservedLayout retorna los campos documentados dentro de los bytes
+// reservados en la revisión indicada, ordenados por posición
+func ReservedLayout(revision string) []ReservedField {
+	return append([]ReservedField(nil), reservedLayouts[revision]...)
+}
+
+// ReservedField retorna los bytes del campo name según la revisión de la
+// respuesta. ok es false si esa revisión no documenta el campo
+func (r *Response) ReservedField(name string) (raw []byte, ok bool) {
+	for _, f := range reservedLayouts[r.Revision] {
+		if f.Name == name {
+			start := f.Offset - ReservedOffset
+			return append([]byte(nil), r.reserved[start:start+f.Size]...), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,90 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+// withLayout registra una revisión de prueba para la versión de firmware
+// indicada mientras dura el test
+func withLayout(t *testing.T, version byte, revision string, fields []ReservedField) {
+	t.Helper()
+	firmwareRevisions[version] = revision
+	reservedLayouts[revision] = fields
+	t.Cleanup(func() {
+		delete(firmwareRevisions, version)
+		delete(reservedLayouts, revision)
+	})
+}
+
+// reservedResponse retorna una respuesta válida con la versión y los bytes
+// reservados indicados
+func reservedResponse(version, r0, r1 byte) []byte {
+	frame := []byte{0x7F, version, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x07, 0xF0, 0x55, 0x20, r0, r1, 0x00}
+	frame[ResponseSize-1] = ChecksumTx(frame[1 : ResponseSize-1])
+	return frame
+}
+
+func TestReservedFieldsByRevision(t *testing.T) {
+	const revision = "ds205a-rs485/test"
+	withLayout(t, 0x09, revision, []ReservedField{{Name: "Temperature", Offset: ReservedOffset, Size: 1}})
+
+	tests := []struct {
+		name     string
+		version  byte
+		revision string
+		field    []byte
+		fields   []string
+	}{
+		{"documented revision", 0x01, Revision, nil, []string{"Reserved"}},
+		{"firmware with named field", 0x09, revision, []byte{0x2A}, []string{"Temperature", "Reserved"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := reservedResponse(tt.version, 0x2A, 0x07)
+
+			response, err := ParseResponse(data, 0x01)
+			if err != nil {
+				t.Fatalf("ParseResponse: %v", err)
+			}
+			if response.Revision != tt.revision {
+				t.Errorf("Revision = %q, want %q", response.Revision, tt.revision)
+			}
+			raw, ok := response.ReservedField("Temperature")
+			if ok != (tt.field != nil) || !bytes.Equal(raw, tt.field) {
+				t.Errorf("ReservedField = % X, %v; want % X", raw, ok, tt.field)
+			}
+			if got := response.Reserved(); !bytes.Equal(got, []byte{0x2A, 0x07}) {
+				t.Errorf("Reserved = % X, want 2A 07", got)
+			}
+
+			var names []string
+			for _, f := range DecodeFrame(data).Fields {
+				if f.Offset >= ReservedOffset && f.Offset < ReservedOffset+ReservedSize {
+					names = append(names, f.Name)
+				}
+			}
+			if len(names) != len(tt.fields) {
+				t.Fatalf("decoded reserved fields = %v, want %v", names, tt.fields)
+			}
+			for i := range names {
+				if names[i] != tt.fields[i] {
+					t.Errorf("decoded reserved fields = %v, want %v", names, tt.fields)
+				}
+			}
+		})
+	}
+}
+
+func TestReservedLayoutsInRange(t *testing.T) {
+	for revision, fields := range reservedLayouts {
+		next := ReservedOffset
+		for _, f := range fields {
+			if f.Offset < next || f.Size <= 0 || f.Offset+f.Size > ReservedOffset+ReservedSize {
+				t.Errorf("revision %s: field %+v overlaps or is outside the reserved bytes", revision, f)
+			}
+			next = f.Offset + f.Size
+		}
+	}
+}
@@ -13,6 +13,7 @@ type Config struct {
 	Parity       string        // Paridad
 	ReadTimeout  time.Duration // Timeout de lectura
 	WriteTimeout time.Duration // Timeout de escritura
+	LockDir      string        // Directorio del archivo de bloqueo entre procesos (vacío: sin bloqueo)
 }
 
 // Logger interface para logging en RS485
@@ -24,6 +25,7 @@ type Logger interface {
 type Connection struct {
 	config *Config
 	port   SerialPort
+	lock   *portLock
 	closed bool
 }
 
@@ -57,24 +59,49 @@ func NewConnection(config *Config) (*Connection, error) {
 	}, nil
 }
 
+// NewConnectionWithPort crea una conexión RS485 sobre un puerto ya construido
+// (por ejemplo, un puerto simulado)
+func NewConnectionWithPort(config *Config, port SerialPort) (*Connection, error) {
+	if config == nil || port == nil {
+		return nil, ErrInvalidConfig
+	}
+
+	return &Connection{
+		config: config,
+		port:   port,
+		closed: true,
+	}, nil
+}
+
 // Open abre la conexión
 func (c *Connection) Open() error {
 	if !c.closed {
 		return nil
 	}
 
+	if c.config.LockDir != "" {
+		lock, err := acquireLock(c.config.LockDir, c.config.Port)
+		if err != nil {
+			return err
+		}
+		c.lock = lock
+	}
+
 	if err := c.port.Open(); err != nil {
+		c.releaseLock()
 		return err
 	}
 
 	// Configurar timeouts
 	if err := c.port.SetReadTimeout(c.config.ReadTimeout); err != nil {
 		c.port.Close()
+		c.releaseLock()
 		return err
 	}
 
 	if err := c.port.SetWriteTimeout(c.config.WriteTimeout); err != nil {
 		c.port.Close()
+		c.releaseLock()
 		return err
 	}
 
@@ -89,10 +116,19 @@ func (c *Connection) Close() error {
 	}
 
 	err := c.port.Close()
+	c.releaseLock()
 	c.closed = true
 	return err
 }
 
+// releaseLock libera el bloqueo entre procesos si fue tomado
+func (c *Connection) releaseLock() {
+	if c.lock != nil {
+		c.lock.release()
+		c.lock = nil
+	}
+}
+
 // Read lee datos de la conexión
 func (c *Connection) Read(p []byte) (int, error) {
 	if c.closed {
@@ -0,0 +1,102 @@
+package rs485
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+
+	"go.bug.st/serial"
+)
+
+var (
+	ErrPermissionDenied = errors.New("permission denied on serial port")
+	ErrPortBusy         = errors.New("serial port busy")
+	ErrNotSerialPort    = errors.New("not a serial port")
+	ErrDeviceUnplugged  = errors.New("serial device disconnected")
+)
+
+// PortError describe un fallo del puerto serial clasificado en uno de los
+// errores exportados, con una sugerencia de recuperación para diagnóstico
+type PortError struct {
+	Op   string // Operación: "open", "read" o "write"
+	Port string // Puerto serial
+	Kind error  // ErrPermissionDenied, ErrPortNotFound, ErrPortBusy, ErrNotSerialPort o ErrDeviceUnplugged
+	Err  error  // Error original del sistema o de la librería serial
+}
+
+func (e *PortError) Error() string {
+	return fmt.Sprintf("%s %s: %v: %v", e.Op, e.Port, e.Kind, e.Err)
+}
+
+// Unwrap permite usar errors.Is con Kind, con ErrOpenFailed (en aperturas)
+// y con el error original
+func (e *PortError) Unwrap() []error {
+	errs := []error{e.Kind}
+	if e.Op == "open" {
+		errs = append(errs, ErrOpenFailed)
+	}
+	return append(errs, e.Err)
+}
+
+// Hint retorna una sugerencia para resolver el problema
+func (e *PortError) Hint() string {
+	switch e.Kind {
+	case ErrPermissionDenied:
+		return fmt.Sprintf("check the permissions of %s; on Linux add the user to the 'dialout' group (sudo usermod -aG dialout $USER) and log in again", e.Port)
+	case ErrPortNotFound:
+		return fmt.Sprintf("check that the USB-RS485 adapter is connected and that %s is the right port (ls /dev/ttyUSB* /dev/ttyACM*)", e.Port)
+	case ErrPortBusy:
+		return fmt.Sprintf("another process is using %s; stop it before retrying (lsof %s)", e.Port, e.Port)
+	case ErrNotSerialPort:
+		return fmt.Sprintf("%s is not a serial device; check the port name", e.Port)
+	case ErrDeviceUnplugged:
+		return "the serial adapter was disconnected; check the cable and USB connection, then reopen the device"
+	default:
+		return ""
+	}
+}
+
+// classifyError traduce errores del sistema o de go.bug.st/serial a un
+// PortError. Retorna nil si el error no corresponde a un caso conocido
+func classifyError(op, port string, err error) *PortError {
+	kind := errorKind(err)
+	if kind == nil {
+		return nil
+	}
+	return &PortError{Op: op, Port: port, Kind: kind, Err: err}
+}
+
+// errorKind determina la clase de fallo del puerto
+func errorKind(err error) error {
+	var portErr *serial.PortError
+	if errors.As(err, &portErr) {
+		switch portErr.Code() {
+		case serial.PermissionDenied:
+			return ErrPermissionDenied
+		case serial.PortNotFound:
+			return ErrPortNotFound
+		case serial.PortBusy:
+			return ErrPortBusy
+		case serial.InvalidSerialPort:
+			return ErrNotSerialPort
+		case serial.PortClosed:
+			return ErrDeviceUnplugged
+		}
+	}
+
+	switch {
+	case errors.Is(err, fs.ErrPermission), errors.Is(err, syscall.EACCES):
+		return ErrPermissionDenied
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, syscall.ENOENT):
+		return ErrPortNotFound
+	case errors.Is(err, syscall.EBUSY):
+		return ErrPortBusy
+	case errors.Is(err, syscall.ENOTTY):
+		return ErrNotSerialPort
+	case errors.Is(err, syscall.EIO), errors.Is(err, syscall.ENXIO), errors.Is(err, syscall.ENODEV):
+		return ErrDeviceUnplugged
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package rs485
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrPortLocked indica que otro proceso tiene tomado el bloqueo del puerto
+	ErrPortLocked = errors.New("serial port locked by another process")
+	// ErrLockUnsupported indica que la plataforma no permite bloquear el puerto
+	ErrLockUnsupported = errors.New("port locking not supported on this platform")
+)
+
+// PortLockedError describe un bloqueo en poder de otro proceso
+type PortLockedError struct {
+	Port string // Puerto serial
+	Path string // Archivo de bloqueo
+	PID  int    // PID del proceso dueño (0 si no se pudo leer)
+}
+
+func (e *PortLockedError) Error() string {
+	if e.PID > 0 {
+		return fmt.Sprintf("%v: %s (lock %s held by pid %d)", ErrPortLocked, e.Port, e.Path, e.PID)
+	}
+	return fmt.Sprintf("%v: %s (lock %s)", ErrPortLocked, e.Port, e.Path)
+}
+
+// Unwrap permite usar errors.Is con ErrPortLocked
+func (e *PortLockedError) Unwrap() error {
+	return ErrPortLocked
+}
+
+// Hint retorna una sugerencia para resolver el problema
+func (e *PortLockedError) Hint() string {
+	if e.PID > 0 {
+		return fmt.Sprintf("process %d is driving %s; stop it before retrying (ps -p %d)", e.PID, e.Port, e.PID)
+	}
+	return fmt.Sprintf("another process is driving %s; stop it before retrying", e.Port)
+}
+
+// LockPath retorna el archivo de bloqueo del puerto dentro de dir, con el
+// nombre estilo UUCP (LCK..ttyUSB0)
+func LockPath(dir, port string) string {
+	return filepath.Join(dir, "LCK.."+filepath.Base(port))
+}
+
+// portLock es un bloqueo consultivo entre procesos sobre un puerto serial
+type portLock struct {
+	path string
+	file *os.File
+}
+
+// acquireLock toma el bloqueo del puerto y escribe el PID propio en el
+// archivo. Respeta el protocolo UUCP de otros programas (minicom, lockdev),
+// que solo escriben su PID sin flock: si el archivo nombra un proceso vivo
+// el puerto se considera tomado. Un PID de un proceso que ya no existe es
+// un bloqueo abandonado y se reemplaza. Si otro proceso tiene el bloqueo,
+// retorna un *PortLockedError
+func acquireLock(dir, port string) (*portLock, error) {
+	path := LockPath(dir, port)
+	if !lockSupported {
+		// No crear el archivo: otras herramientas UUCP lo respetarían
+		return nil, fmt.Errorf("failed to lock %s: %w", path, ErrLockUnsupported)
+	}
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create lock file %s: %w", path, err)
+		}
+
+		if err := tryLock(file); err != nil {
+			pid := readLockPID(file)
+			file.Close()
+			if errors.Is(err, ErrPortLocked) {
+				return nil, &PortLockedError{Port: port, Path: path, PID: pid}
+			}
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		// El dueño anterior pudo eliminar el archivo entre la apertura y el
+		// flock: en ese caso el bloqueo tomado es de un archivo huérfano
+		if !samePath(file, path) {
+			unlock(file)
+			file.Close()
+			continue
+		}
+
+		if pid := readLockPID(file); pid > 0 && pid != os.Getpid() && processAlive(pid) {
+			unlock(file)
+			file.Close()
+			return nil, &PortLockedError{Port: port, Path: path, PID: pid}
+		}
+
+		// Formato HDB: PID en decimal, alineado a 10 caracteres. Sin el PID
+		// otros procesos no sabrían quién tiene el puerto: se suelta el bloqueo
+		lock := &portLock{path: path, file: file}
+		if err := writeLockPID(file); err != nil {
+			lock.release()
+			return nil, fmt.Errorf("failed to write pid to lock file %s: %w", path, err)
+		}
+
+		return lock, nil
+	}
+}
+
+// release libera el bloqueo y elimina el archivo, como espera el protocolo
+// UUCP. Se elimina antes de soltar el flock para que ningún proceso tome un
+// bloqueo sobre el archivo ya eliminado sin notarlo (ver samePath)
+func (l *portLock) release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	os.Remove(l.path)
+	unlock(l.file)
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// samePath indica si path todavía corresponde al archivo abierto
+func samePath(file *os.File, path string) bool {
+	opened, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	return err == nil && os.SameFile(opened, current)
+}
+
+// writeLockPID reemplaza el contenido del archivo por el PID propio
+func writeLockPID(file *os.File) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := file.WriteAt([]byte(fmt.Sprintf("%10d\n", os.Getpid())), 0)
+	return err
+}
+
+// readLockPID lee el PID del dueño del bloqueo
+func readLockPID(file *os.File) int {
+	buf := make([]byte, 32)
+	n, _ := file.ReadAt(buf, 0)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(buf[:n])))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,27 @@
+//go:build !unix
+
+package rs485
+
+import (
+	"os"
+)
+
+// lockSupported es false fuera de sistemas unix: pedir el bloqueo falla
+// con ErrLockUnsupported, sin crear el archivo, en lugar de dar por
+// protegido un puerto que no lo está
+const lockSupported = false
+
+// tryLock no está soportado fuera de sistemas unix
+func tryLock(file *os.File) error {
+	return ErrLockUnsupported
+}
+
+// unlock no hace nada fuera de sistemas unix
+func unlock(file *os.File) error {
+	return nil
+}
+
+// processAlive no se usa fuera de sistemas unix
+func processAlive(pid int) bool {
+	return false
+}
@@ -0,0 +1,98 @@
+//go:build unix
+
+package rs485
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testPort = "/dev/ttyTEST0"
+
+func writeLockFile(t *testing.T, dir string, pid int) string {
+	t.Helper()
+	path := LockPath(dir, testPort)
+	if err := os.WriteFile(path, []byte(strconv.Itoa(pid)+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLockExclusive(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := acquireLock(dir, testPort)
+	if err != nil {
+		t.Fatalf("first acquireLock: %v", err)
+	}
+
+	_, err = acquireLock(dir, testPort)
+	var locked *PortLockedError
+	if !errors.As(err, &locked) || locked.PID != os.Getpid() {
+		t.Fatalf("second acquireLock error = %v, want PortLockedError for pid %d", err, os.Getpid())
+	}
+
+	if err := lock.release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, err := os.Stat(LockPath(dir, testPort)); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind after release (stat error %v)", err)
+	}
+
+	lock, err = acquireLock(dir, testPort)
+	if err != nil {
+		t.Fatalf("acquireLock after release: %v", err)
+	}
+	lock.release()
+}
+
+func TestLockRespectsUUCPFile(t *testing.T) {
+	dir := t.TempDir()
+	// Un bloqueo estilo minicom/lockdev: solo el PID de un proceso vivo
+	owner := os.Getppid()
+	path := writeLockFile(t, dir, owner)
+
+	_, err := acquireLock(dir, testPort)
+	var locked *PortLockedError
+	if !errors.As(err, &locked) || locked.PID != owner {
+		t.Fatalf("acquireLock error = %v, want PortLockedError for pid %d", err, owner)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(data)) != strconv.Itoa(owner) {
+		t.Errorf("foreign lock file changed: %q, %v", data, err)
+	}
+}
+
+func TestLockReplacesStaleUUCPFile(t *testing.T) {
+	dir := t.TempDir()
+	// Mayor que cualquier pid_max: el proceso no puede existir
+	path := writeLockFile(t, dir, 1<<30)
+
+	lock, err := acquireLock(dir, testPort)
+	if err != nil {
+		t.Fatalf("acquireLock over stale lock: %v", err)
+	}
+	defer lock.release()
+
+	data, err := os.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("lock file = %q, %v; want own pid", data, err)
+	}
+}
+
+func TestWriteLockPIDReportsErrors(t *testing.T) {
+	path := writeLockFile(t, t.TempDir(), 1)
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if err := writeLockPID(file); err == nil {
+		t.Error("writeLockPID on a read-only file succeeded")
+	}
+}
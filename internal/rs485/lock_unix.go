@@ -0,0 +1,32 @@
+//go:build unix
+
+package rs485
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockSupported indica que la plataforma permite bloquear el puerto
+const lockSupported = true
+
+// tryLock toma un flock exclusivo sin bloquear
+func tryLock(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrPortLocked
+	}
+	return err
+}
+
+// unlock libera el flock
+func unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+// processAlive indica si existe un proceso con el PID indicado
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,144 @@
+package rs485
+
+import (
+	"sync"
+	"time"
+)
+
+// ScriptedPort implementa SerialPort entregando lecturas predefinidas, una
+// por cada llamada a Read. Permite reproducir respuestas fragmentadas, con
+// ruido previo o intercaladas con eco sin necesidad de hardware
+type ScriptedPort struct {
+	mu      sync.Mutex
+	chunks  [][]byte                // Lecturas pendientes, en orden
+	writes  [][]byte                // Tramas escritas por el cliente
+	open    bool                    // Estado del puerto
+	OnWrite func(p []byte) [][]byte // Opcional: lecturas a encolar tras cada escritura
+}
+
+// NewScriptedPort crea un puerto con las lecturas indicadas ya encoladas
+func NewScriptedPort(chunks ...[]byte) *ScriptedPort {
+	sp := &ScriptedPort{}
+	sp.Enqueue(chunks...)
+	return sp
+}
+
+// Enqueue agrega lecturas al final del guion
+func (sp *ScriptedPort) Enqueue(chunks ...[]byte) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	for _, c := range chunks {
+		sp.chunks = append(sp.chunks, append([]byte(nil), c...))
+	}
+}
+
+// Writes retorna una copia de las tramas escritas hasta el momento
+func (sp *ScriptedPort) Writes() [][]byte {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	writes := make([][]byte, len(sp.writes))
+	for i, w := range sp.writes {
+		writes[i] = append([]byte(nil), w...)
+	}
+	return writes
+}
+
+// Pending retorna el número de lecturas que aún no se han entregado
+func (sp *ScriptedPort) Pending() int {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return len(sp.chunks)
+}
+
+// Open abre el puerto
+func (sp *ScriptedPort) Open() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.open = true
+	return nil
+}
+
+// Close cierra el puerto
+func (sp *ScriptedPort) Close() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.open = false
+	return nil
+}
+
+// Read entrega la siguiente lectura del guion. Si el guion está vacío se
+// comporta como un timeout del puerto real (0 bytes, sin error). Si la
+// lectura no cabe en p, el resto se entrega en la siguiente llamada
+func (sp *ScriptedPort) Read(p []byte) (int, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if !sp.open {
+		return 0, ErrConnectionClosed
+	}
+
+	if len(sp.chunks) == 0 {
+		return 0, nil
+	}
+
+	chunk := sp.chunks[0]
+	n := copy(p, chunk)
+	if n < len(chunk) {
+		sp.chunks[0] = chunk[n:]
+	} else {
+		sp.chunks = sp.chunks[1:]
+	}
+	return n, nil
+}
+
+// Write registra la trama escrita y, si OnWrite está definido, encola
+// las lecturas que retorne
+func (sp *ScriptedPort) Write(p []byte) (int, error) {
+	sp.mu.Lock()
+	if !sp.open {
+		sp.mu.Unlock()
+		return 0, ErrConnectionClosed
+	}
+	sp.writes = append(sp.writes, append([]byte(nil), p...))
+	onWrite := sp.OnWrite
+	sp.mu.Unlock()
+
+	if onWrite != nil {
+		sp.Enqueue(onWrite(p)...)
+	}
+	return len(p), nil
+}
+
+// Flush descarta las lecturas pendientes
+func (sp *ScriptedPort) Flush() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.chunks = nil
+	return nil
+}
+
+// SetReadTimeout no tiene efecto en el puerto guionizado
+func (sp *ScriptedPort) SetReadTimeout(timeout time.Duration) error {
+	return nil
+}
+
+// SetWriteTimeout no tiene efecto en el puerto guionizado
+func (sp *ScriptedPort) SetWriteTimeout(timeout time.Duration) error {
+	return nil
+}
+
+// SplitEvery divide data en fragmentos de tamaño n (el último puede ser menor)
+func SplitEvery(data []byte, n int) [][]byte {
+	if n <= 0 {
+		n = 1
+	}
+	var chunks [][]byte
+	for len(data) > n {
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+	}
+	if len(data) > 0 {
+		chunks = append(chunks, data)
+	}
+	return chunks
+}
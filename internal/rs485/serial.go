@@ -43,6 +43,9 @@ func (sp *serialPort) Open() error {
 
 	port, err := serial.Open(sp.config.Port, mode)
 	if err != nil {
+		if portErr := classifyError("open", sp.config.Port, err); portErr != nil {
+			return portErr
+		}
 		return fmt.Errorf("%w: %v", ErrOpenFailed, err)
 	}
 
@@ -68,6 +71,11 @@ func (sp *serialPort) Read(p []byte) (int, error) {
 	}
 
 	n, err := sp.port.Read(p)
+	if err != nil {
+		if portErr := classifyError("read", sp.config.Port, err); portErr != nil {
+			return n, portErr
+		}
+	}
 	return n, err
 }
 
@@ -77,7 +85,13 @@ func (sp *serialPort) Write(p []byte) (int, error) {
 		return 0, ErrConnectionClosed
 	}
 
-	return sp.port.Write(p)
+	n, err := sp.port.Write(p)
+	if err != nil {
+		if portErr := classifyError("write", sp.config.Port, err); portErr != nil {
+			return n, portErr
+		}
+	}
+	return n, err
 }
 
 // Flush limpia los buffers del puerto serial
@@ -86,9 +100,8 @@ func (sp *serialPort) Flush() error {
 		return ErrConnectionClosed
 	}
 
-	// La librería go.bug.st/serial no expone un método flush directo
-	// Pero podemos intentar drenar el buffer de lectura
-	return nil
+	// Descartar los bytes recibidos que aún no se han leído
+	return sp.port.ResetInputBuffer()
 }
 
 // SetReadTimeout configura el timeout de lectura
@@ -2,9 +2,11 @@ package ds205a
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/dumacp/ds205a/internal/device"
+	"github.com/dumacp/ds205a/internal/protocol"
 )
 
 // Direction representa la dirección de paso
@@ -27,6 +29,33 @@ const (
 	LogLevelDebug  = device.LogLevelDebug  // Todos los logs
 )
 
+// Logger interface para logging personalizable
+type Logger = device.Logger
+
+// LogFormat representa el formato de salida del logger por defecto
+type LogFormat = device.LogFormat
+
+// Formatos de salida del logger por defecto
+const (
+	LogFormatText = device.LogFormatText // Texto: [LEVEL] mensaje [args]
+	LogFormatJSON = device.LogFormatJSON // Un objeto JSON por línea
+)
+
+// LoggerConfig configura destino, formato y marcas de tiempo del logger por defecto
+type LoggerConfig = device.LoggerConfig
+
+// NewLogger crea el logger por defecto con la configuración indicada.
+//
+//	logger := ds205a.NewLogger(ds205a.LoggerConfig{
+//		Level:      ds205a.LogLevelInfo,
+//		Output:     os.Stderr,
+//		Format:     ds205a.LogFormatJSON,
+//		Timestamps: true,
+//	})
+func NewLogger(config LoggerConfig) Logger {
+	return device.NewLogger(config)
+}
+
 // PassageDirection representa la dirección de paso específica del dispositivo
 type PassageDirection = device.PassageDirection
 
@@ -36,24 +65,183 @@ const (
 	PassageDirectionExit  = device.PassageDirectionExit  // Salida
 )
 
+// Orientation indica hacia qué lado físico del torniquete queda la entrada
+type Orientation = device.Orientation
+
+const (
+	EntryIsLeft  = device.EntryIsLeft  // La entrada es el paso izquierdo (default)
+	EntryIsRight = device.EntryIsRight // La entrada es el paso derecho
+)
+
+// Option configura aspectos opcionales del torniquete
+type Option = device.Option
+
+// WithOrientation define la orientación de instalación; las APIs de
+// entrada/salida y los contadores EntryCount/ExitCount la respetan
+func WithOrientation(o Orientation) Option {
+	return device.WithOrientation(o)
+}
+
+// WithPortLock toma un bloqueo entre procesos en dir (ej: "/var/lock") al
+// abrir el puerto; un segundo proceso, o un bloqueo UUCP vigente de otro
+// programa, produce ErrPortLocked. Fuera de unix Open falla con
+// ErrLockUnsupported
+func WithPortLock(dir string) Option {
+	return device.WithPortLock(dir)
+}
+
+// WithReadBuffer define los bytes por lectura del puerto (default 32) y el
+// máximo acumulado buscando una trama (default 256). 0 conserva el valor
+// por defecto; los valores fuera de rango hacen fallar New con ErrInvalidConfig
+func WithReadBuffer(chunkSize, maxBuffer int) Option {
+	return device.WithReadBuffer(chunkSize, maxBuffer)
+}
+
+// WithTraceWriter escribe en w una línea por transacción (hora, comando,
+// resultado, latencia, reintentos y metadatos del contexto) sin importar el
+// nivel de log
+func WithTraceWriter(w io.Writer) Option {
+	return device.WithTraceWriter(w)
+}
+
+// BusyPolicy define qué hacer cuando el dispositivo responde ocupado
+type BusyPolicy = device.BusyPolicy
+
+// Políticas ante un rechazo por dispositivo ocupado
+const (
+	BusyFailFast = device.BusyFailFast // Retornar ErrDeviceBusy de inmediato (default)
+	BusyRetry    = device.BusyRetry    // Reenviar el comando cada intervalo hasta que lo acepte
+	BusyWaitIdle = device.BusyWaitIdle // Esperar la puerta cerrada y reenviar
+)
+
+// DefaultBusyPollInterval es el intervalo por defecto entre intentos
+const DefaultBusyPollInterval = device.DefaultBusyPollInterval
+
+// WithBusyPolicy configura la respuesta a un rechazo por dispositivo
+// ocupado; la espera está acotada por el timeout y por el contexto, y el
+// bus queda libre para otros comandos entre intentos
+func WithBusyPolicy(policy BusyPolicy, poll time.Duration) Option {
+	return device.WithBusyPolicy(policy, poll)
+}
+
+// DefaultTelemetryShare es la fracción del tiempo de bus que la telemetría
+// puede usar por defecto con WithTelemetryBudget
+const DefaultTelemetryShare = device.DefaultTelemetryShare
+
+// WithTelemetryBudget da prioridad a los comandos de control sobre las
+// consultas de estado, limita la telemetría a share del tiempo de bus y
+// hace que una consulta con reintentos ceda el bus tras maxControlDelay si
+// hay un comando esperando. Las esperas logradas se reportan en Stats
+func WithTelemetryBudget(share float64, maxControlDelay time.Duration) Option {
+	return device.WithTelemetryBudget(share, maxControlDelay)
+}
+
+// WithSimulatedResponses reemplaza el puerto serial por un simulador que
+// responde a todos los comandos, para demos y pruebas de interfaz sin hardware
+func WithSimulatedResponses() Option {
+	return device.WithSimulatedResponses()
+}
+
 // Status representa el estado del dispositivo
 type Status = device.Status
 
+// GateState es el estado de la puerta derivado del byte GateStatus
+type GateState = device.GateState
+
+const (
+	GateStateUnknown = device.GateStateUnknown // Valor sin mapeo conocido (incluye puerta abierta)
+	GateStateClosed  = device.GateStateClosed  // Puerta cerrada
+	GateStateFault   = device.GateStateFault   // Falla reportada por el equipo
+)
+
+// WithGateStates registra valores de GateStatus no documentados observados
+// en un firmware, para que Status.GateState los interprete
+func WithGateStates(states map[byte]GateState) Option {
+	return device.WithGateStates(states)
+}
+
+// TurnstileState es el estado del torniquete visto por el controlador
+type TurnstileState = device.TurnstileState
+
+const (
+	StateIdle       = device.StateIdle       // En reposo
+	StateGranted    = device.StateGranted    // Paso autorizado
+	StatePassing    = device.StatePassing    // Peatón cruzando
+	StateClosing    = device.StateClosing    // Cierre en curso
+	StateAlwaysOpen = device.StateAlwaysOpen // Siempre abierto
+	StateForbidden  = device.StateForbidden  // Paso prohibido
+	StateFault      = device.StateFault      // Falla reportada por el equipo
+)
+
+// StateMachine valida las transiciones de estado y notifica los cambios
+type StateMachine = device.StateMachine
+
+// TransitionHook se invoca después de cada cambio de estado
+type TransitionHook = device.TransitionHook
+
+// TransitionEvent describe un cambio de estado junto con la huella del
+// equipo que lo produjo
+type TransitionEvent = device.TransitionEvent
+
+// TransitionEventHook se invoca después de cada cambio de estado con el
+// evento completo
+type TransitionEventHook = device.TransitionEventHook
+
+// ErrInvalidTransition indica una transición de estado no permitida
+var ErrInvalidTransition = device.ErrInvalidTransition
+
+// CommandResult es la confirmación de un comando de control (estado
+// reportado en la respuesta, latencia e intentos)
+type CommandResult = device.CommandResult
+
 // DeviceInfo contiene información del dispositivo
 type DeviceInfo = device.DeviceInfo
 
-// Turnstile representa un dispositivo turnstile DS205A
+// Fingerprint identifica un equipo (número de máquina, firmware y particularidades)
+type Fingerprint = device.Fingerprint
+
+// Particularidades de firmware reportadas en Fingerprint.Quirks
+const (
+	QuirkNonStandardChecksum    = device.QuirkNonStandardChecksum
+	QuirkReservedBytesUsed      = device.QuirkReservedBytesUsed
+	QuirkUndocumentedGateStatus = device.QuirkUndocumentedGateStatus
+)
+
+// Response representa la trama de respuesta decodificada del dispositivo
+type Response = protocol.Response
+
+// Stats contiene contadores de la comunicación (comandos, reintentos, colisiones)
+type Stats = device.Stats
+
+// Step representa un paso de una secuencia (acción y compensación)
+type Step = device.Step
+
+// Sequence es una lista ordenada de pasos que se completa entera o se compensa
+type Sequence = device.Sequence
+
+// SequenceError describe el fallo de una secuencia y su rollback
+type SequenceError = device.SequenceError
+
+// Turnstile representa un dispositivo turnstile DS205A. Sus métodos pueden
+// llamarse desde varias goroutines: por ejemplo, un lazo de GetStatus puede
+// seguir consultando mientras otra goroutine abre y cierra pasos, sin
+// corromper tramas (ver device.Device)
 type Turnstile struct {
 	device *device.Device
 }
 
 // New crea una nueva instancia de Turnstile
-func New(port string, machineNumber uint8, baudRate int, timeout time.Duration) (*Turnstile, error) {
-	return NewWithLogLevel(port, machineNumber, baudRate, timeout, device.LogLevelSilent)
+func New(port string, machineNumber uint8, baudRate int, timeout time.Duration, opts ...Option) (*Turnstile, error) {
+	return NewWithLogLevel(port, machineNumber, baudRate, timeout, device.LogLevelSilent, opts...)
 }
 
 // NewWithLogLevel crea una nueva instancia de Turnstile con nivel de logging específico
-func NewWithLogLevel(port string, machineNumber uint8, baudRate int, timeout time.Duration, logLevel device.LogLevel) (*Turnstile, error) {
+func NewWithLogLevel(port string, machineNumber uint8, baudRate int, timeout time.Duration, logLevel device.LogLevel, opts ...Option) (*Turnstile, error) {
+	return NewWithLogger(port, machineNumber, baudRate, timeout, device.GetLoggerWithLevel(logLevel), opts...)
+}
+
+// NewWithLogger crea una nueva instancia de Turnstile con un logger personalizado
+func NewWithLogger(port string, machineNumber uint8, baudRate int, timeout time.Duration, logger Logger, opts ...Option) (*Turnstile, error) {
 	config := &device.Config{
 		Port:         port,
 		BaudRate:     baudRate,
@@ -61,13 +249,13 @@ func NewWithLogLevel(port string, machineNumber uint8, baudRate int, timeout tim
 		StopBits:     1,
 		Parity:       "none",
 		Timeout:      timeout,
-		ReadTimeout:  2 * time.Second,
-		WriteTimeout: 2 * time.Second,
+		ReadTimeout:  0, // Adaptativo según baudrate
+		WriteTimeout: 0, // Adaptativo según baudrate
 		DeviceID:     machineNumber,
 		RetryCount:   3,
 	}
 
-	dev, err := device.NewWithLogger(config, device.GetLoggerWithLevel(logLevel))
+	dev, err := device.NewWithLogger(config, logger, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +265,52 @@ func NewWithLogLevel(port string, machineNumber uint8, baudRate int, timeout tim
 	}, nil
 }
 
+// Config es la configuración completa del dispositivo, para aplicaciones
+// que cargan configuraciones de varios equipos (ver NewFromConfig)
+type Config = device.Config
+
+// FieldError describe un problema en un campo de Config
+type FieldError = device.FieldError
+
+// ConfigError agrupa todos los problemas de una Config (ver Config.Validate)
+type ConfigError = device.ConfigError
+
+// ErrInvalidConfig indica una configuración inválida. Usar con errors.Is
+var ErrInvalidConfig = device.ErrInvalidConfig
+
+// NewFromConfig crea un Turnstile a partir de una configuración completa.
+// La configuración se valida con Config.Validate, que reporta todos los
+// problemas a la vez
+func NewFromConfig(config *Config, opts ...Option) (*Turnstile, error) {
+	dev, err := device.New(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Turnstile{
+		device: dev,
+	}, nil
+}
+
+// ContextWithReadTimeout retorna un contexto que reemplaza el timeout de
+// lectura para los comandos ejecutados con él.
+//
+//	ctx := ds205a.ContextWithReadTimeout(ctx, 10*time.Second)
+//	err := t.Reset(ctx)
+func ContextWithReadTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return device.ContextWithReadTimeout(ctx, timeout)
+}
+
+// Metadata contiene datos del origen de un comando (operador, transacción)
+type Metadata = device.Metadata
+
+// ContextWithMetadata asocia metadatos a los comandos ejecutados con el
+// contexto; se incluyen en los logs de auditoría para vincular, por ejemplo,
+// una apertura con la transacción de pago que la originó
+func ContextWithMetadata(ctx context.Context, md Metadata) context.Context {
+	return device.ContextWithMetadata(ctx, md)
+}
+
 // Open abre la conexión con el dispositivo
 func (t *Turnstile) Open() error {
 	return t.device.Open()
@@ -87,6 +321,19 @@ func (t *Turnstile) Close() error {
 	return t.device.Close()
 }
 
+// RawConn reserva el bus y retorna una conexión de bytes sin procesar.
+// Los comandos quedan suspendidos hasta que se cierre la conexión; cerrarla
+// no cierra el puerto serial. Si el dispositivo se cierra o se reabre, la
+// conexión retorna ErrDeviceClosed
+func (t *Turnstile) RawConn(ctx context.Context) (io.ReadWriteCloser, error) {
+	return t.device.RawConn(ctx)
+}
+
+// Stats retorna los contadores de comunicación del dispositivo
+func (t *Turnstile) Stats() Stats {
+	return t.device.Stats()
+}
+
 // GetStatus obtiene el estado actual del dispositivo
 func (t *Turnstile) GetStatus(ctx context.Context) (*Status, error) {
 	return t.device.GetStatus(ctx)
@@ -97,8 +344,45 @@ func (t *Turnstile) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
 	return t.device.GetDeviceInfo(ctx)
 }
 
+// Fingerprint retorna la huella del equipo para inventario de flotas
+func (t *Turnstile) Fingerprint(ctx context.Context) (*Fingerprint, error) {
+	return t.device.Fingerprint(ctx)
+}
+
+// OpenPassage abre el paso en la dirección lógica indicada (entrada/salida)
+func (t *Turnstile) OpenPassage(ctx context.Context, dir PassageDirection, value uint8) (*CommandResult, error) {
+	return t.device.OpenPassage(ctx, dir, value)
+}
+
+// AlwaysOpenPassage mantiene siempre abierto el paso en la dirección lógica indicada
+func (t *Turnstile) AlwaysOpenPassage(ctx context.Context, dir PassageDirection) error {
+	return t.device.AlwaysOpenPassage(ctx, dir)
+}
+
+// ForbidPassage prohíbe el paso en la dirección lógica indicada
+func (t *Turnstile) ForbidPassage(ctx context.Context, dir PassageDirection) error {
+	return t.device.ForbidPassage(ctx, dir)
+}
+
+// ResetPassageCounters resetea el contador de la dirección lógica indicada
+func (t *Turnstile) ResetPassageCounters(ctx context.Context, dir PassageDirection) error {
+	return t.device.ResetPassageCounters(ctx, dir)
+}
+
+// StateMachine retorna la máquina de estados compartida del torniquete. Se
+// actualiza con cada respuesta del equipo; los módulos que detectan el paso
+// de peatones declaran StatePassing con Transition
+func (t *Turnstile) StateMachine() *StateMachine {
+	return t.device.StateMachine()
+}
+
+// Orientation retorna la orientación de instalación configurada
+func (t *Turnstile) Orientation() Orientation {
+	return t.device.Orientation()
+}
+
 // LeftOpen abre el paso por la izquierda (permite que el valor especifique parámetros)
-func (t *Turnstile) LeftOpen(ctx context.Context, value uint8) error {
+func (t *Turnstile) LeftOpen(ctx context.Context, value uint8) (*CommandResult, error) {
 	return t.device.LeftOpen(ctx, value)
 }
 
@@ -108,7 +392,7 @@ func (t *Turnstile) LeftAlwaysOpen(ctx context.Context) error {
 }
 
 // RightOpen abre el paso por la derecha (permite que el valor especifique parámetros)
-func (t *Turnstile) RightOpen(ctx context.Context, value uint8) error {
+func (t *Turnstile) RightOpen(ctx context.Context, value uint8) (*CommandResult, error) {
 	return t.device.RightOpen(ctx, value)
 }
 
@@ -118,7 +402,7 @@ func (t *Turnstile) RightAlwaysOpen(ctx context.Context) error {
 }
 
 // CloseGate cierra la puerta/torniquete
-func (t *Turnstile) CloseGate(ctx context.Context) error {
+func (t *Turnstile) CloseGate(ctx context.Context) (*CommandResult, error) {
 	return t.device.CloseGate(ctx)
 }
 
@@ -156,3 +440,24 @@ func (t *Turnstile) Reset(ctx context.Context) error {
 func (t *Turnstile) SetParameters(ctx context.Context, value1 uint8, value2 uint8) error {
 	return t.device.SetParameters(ctx, []byte{value1, value2})
 }
+
+// Do ejecuta una secuencia de comandos en orden. Si un paso falla se
+// aplican las compensaciones de los pasos previos en orden inverso. No es
+// atómica: comandos de otras goroutines pueden ejecutarse entre los pasos.
+//
+//	err := t.Do(ctx, ds205a.Sequence{
+//		{Name: "forbid-right", Do: t.ForbiddenRightPassage, Undo: t.DisablePassageRestrictions},
+//		{Name: "left-open", Do: func(ctx context.Context) error { _, err := t.LeftOpen(ctx, 1); return err }},
+//	})
+func (t *Turnstile) Do(ctx context.Context, seq Sequence) error {
+	return t.device.Do(ctx, seq)
+}
+
+// SendRaw envía un comando arbitrario al dispositivo usando el mismo
+// framing, reintentos y decodificación que la API tipada. Está pensado para
+// comandos que la API aún no cubre; data admite hasta 3 bytes.
+//
+//	resp, err := t.SendRaw(ctx, byte(ds205a.CmdGetStatus), nil)
+func (t *Turnstile) SendRaw(ctx context.Context, cmd byte, data []byte) (*Response, error) {
+	return t.device.SendCommand(ctx, protocol.CommandType(cmd), data)
+}
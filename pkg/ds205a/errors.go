@@ -0,0 +1,87 @@
+package ds205a
+
+import (
+	"errors"
+
+	"github.com/dumacp/ds205a/internal/device"
+	"github.com/dumacp/ds205a/internal/rs485"
+	"github.com/dumacp/ds205a/pkg/i18n"
+)
+
+// Errores del puerto serial. Usar con errors.Is
+var (
+	ErrPermissionDenied = rs485.ErrPermissionDenied // Sin permisos sobre el puerto
+	ErrPortNotFound     = rs485.ErrPortNotFound     // El puerto no existe
+	ErrPortBusy         = rs485.ErrPortBusy         // El puerto está en uso por otro proceso
+	ErrNotSerialPort    = rs485.ErrNotSerialPort    // La ruta no es un puerto serial
+	ErrDeviceUnplugged  = rs485.ErrDeviceUnplugged  // El adaptador se desconectó
+	ErrPortLocked       = rs485.ErrPortLocked       // Otro proceso tiene el bloqueo del puerto
+	ErrLockUnsupported  = rs485.ErrLockUnsupported  // La plataforma no permite bloquear el puerto
+)
+
+// ErrConcurrentAccess indica un acceso directo al bus (RawConn) que se
+// solaparía con otra operación en curso
+var ErrConcurrentAccess = device.ErrConcurrentAccess
+
+// ErrDeviceClosed indica un RawConn usado después de cerrar (o reabrir) el
+// dispositivo
+var ErrDeviceClosed = device.ErrDeviceClosed
+
+// ErrDeviceBusy indica que el dispositivo rechazó el comando por estar
+// ocupado (ver WithBusyPolicy)
+var ErrDeviceBusy = device.ErrDeviceBusy
+
+// ErrPreempted indica una consulta de estado que cedió el bus a un comando
+// de control (ver WithTelemetryBudget)
+var ErrPreempted = device.ErrPreempted
+
+// PortError describe un fallo del puerto serial con una sugerencia de recuperación
+type PortError = rs485.PortError
+
+// PortLockedError indica el proceso que tiene el bloqueo del puerto
+type PortLockedError = rs485.PortLockedError
+
+// Hint retorna la sugerencia de recuperación asociada al error, o una
+// cadena vacía si el error no proviene de un fallo conocido del puerto
+func Hint(err error) string {
+	var portErr *PortError
+	if errors.As(err, &portErr) {
+		return portErr.Hint()
+	}
+	var lockErr *PortLockedError
+	if errors.As(err, &lockErr) {
+		return lockErr.Hint()
+	}
+	return ""
+}
+
+// LocalizedHint retorna la sugerencia de recuperación en el idioma indicado,
+// o una cadena vacía si el error no proviene de un fallo conocido del puerto
+func LocalizedHint(err error, locale i18n.Locale) string {
+	var lockErr *PortLockedError
+	if errors.As(err, &lockErr) {
+		if lockErr.PID > 0 {
+			return i18n.Text(locale, i18n.HintPortLockedPID, lockErr.Port, lockErr.PID)
+		}
+		return i18n.Text(locale, i18n.HintPortLocked, lockErr.Port)
+	}
+
+	var portErr *PortError
+	if !errors.As(err, &portErr) {
+		return ""
+	}
+	switch portErr.Kind {
+	case ErrPermissionDenied:
+		return i18n.Text(locale, i18n.HintPermissionDenied, portErr.Port)
+	case ErrPortNotFound:
+		return i18n.Text(locale, i18n.HintPortNotFound, portErr.Port)
+	case ErrPortBusy:
+		return i18n.Text(locale, i18n.HintPortBusy, portErr.Port)
+	case ErrNotSerialPort:
+		return i18n.Text(locale, i18n.HintNotSerialPort, portErr.Port)
+	case ErrDeviceUnplugged:
+		return i18n.Text(locale, i18n.HintDeviceUnplugged)
+	default:
+		return portErr.Hint()
+	}
+}
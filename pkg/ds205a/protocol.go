@@ -0,0 +1,80 @@
+package ds205a
+
+import (
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// CommandType representa el código de un comando del protocolo
+type CommandType = protocol.CommandType
+
+// Comandos del protocolo (ver doc/commands.csv)
+const (
+	CmdGetStatus                  = protocol.CmdGetStatus                  // 0x10 Status
+	CmdResetLeftCounters          = protocol.CmdResetLeftCounters          // 0x20 Reset contador izquierda
+	CmdResetRightCounters         = protocol.CmdResetRightCounters         // 0x21 Reset contador derecha
+	CmdRestartDevice              = protocol.CmdRestartDevice              // 0x35 Restart device (requiere 0x60)
+	CmdLeftOpen                   = protocol.CmdLeftOpen                   // 0x80 Abrir izquierda (Value)
+	CmdLeftAlwaysOpen             = protocol.CmdLeftAlwaysOpen             // 0x81 Siempre abierto izquierda
+	CmdRightOpen                  = protocol.CmdRightOpen                  // 0x82 Abrir derecha (Value)
+	CmdRightAlwaysOpen            = protocol.CmdRightAlwaysOpen            // 0x83 Siempre abierto derecha
+	CmdCloseGate                  = protocol.CmdCloseGate                  // 0x84 Cerrar puerta
+	CmdForbiddenLeftPassage       = protocol.CmdForbiddenLeftPassage       // 0x88 Prohibir paso izquierda
+	CmdForbiddenRightPassage      = protocol.CmdForbiddenRightPassage      // 0x89 Prohibir paso derecha
+	CmdDisablePassageRestrictions = protocol.CmdDisablePassageRestrictions // 0x8F Deshabilitar restricciones
+	CmdSetParameters              = protocol.CmdSetParameters              // 0x96 Establecer parámetros
+)
+
+// ResponseCode representa el valor del campo Command Execution de la respuesta
+type ResponseCode = protocol.ResponseCode
+
+// Códigos de respuesta del dispositivo
+const (
+	RespSuccess      = protocol.RespSuccess      // 0x55 Comando ejecutado exitosamente
+	RespError        = protocol.RespError        // 0x01 Error general
+	RespInvalidCmd   = protocol.RespInvalidCmd   // 0x02 Comando inválido
+	RespInvalidParam = protocol.RespInvalidParam // 0x03 Parámetro inválido
+	RespDeviceBusy   = protocol.RespDeviceBusy   // 0x04 Dispositivo ocupado
+	RespTimeout      = protocol.RespTimeout      // 0x05 Timeout
+)
+
+// Constantes de trama del protocolo
+const (
+	FrameHeader    = protocol.FrameHeader    // 0x7E inicio de trama de comando
+	ResponseHeader = protocol.ResponseHeader // 0x7F inicio de trama de respuesta
+	FrameSize      = protocol.FrameSize      // Tamaño de la trama de comando
+	ResponseSize   = protocol.ResponseSize   // Tamaño de la trama de respuesta
+	RestartParam   = protocol.RestartParam   // Parámetro requerido por CmdRestartDevice
+)
+
+// Errores de verificación de tramas. Usar con errors.Is
+var (
+	ErrFrameTooShort    = protocol.ErrFrameTooShort    // La trama tiene menos bytes de los esperados
+	ErrInvalidHeader    = protocol.ErrInvalidHeader    // El primer byte no es el header esperado
+	ErrChecksumMismatch = protocol.ErrChecksumMismatch // El checksum no corresponde a los datos
+)
+
+// ChecksumTx calcula el checksum TX del protocolo (NOT de la suma de los
+// bytes) sobre frame, que no debe incluir el byte de checksum. Es el mismo
+// algoritmo que usa la librería al construir comandos:
+//
+//	cmd[FrameSize-1] = ds205a.ChecksumTx(cmd[:FrameSize-1])
+func ChecksumTx(frame []byte) byte {
+	return protocol.ChecksumTx(frame)
+}
+
+// VerifyRx verifica el checksum RX de una respuesta completa, header
+// incluido. Retorna nil si es correcto, o ErrFrameTooShort,
+// ErrInvalidHeader o ErrChecksumMismatch
+func VerifyRx(frame []byte) error {
+	return protocol.VerifyRx(frame)
+}
+
+// ReservedField es un campo con nombre dentro de los bytes reservados de la
+// respuesta (ver Response.ReservedField)
+type ReservedField = protocol.ReservedField
+
+// ReservedLayout retorna los campos que la revisión del protocolo documenta
+// dentro de los bytes reservados
+func ReservedLayout(revision string) []ReservedField {
+	return protocol.ReservedLayout(revision)
+}
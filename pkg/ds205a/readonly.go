@@ -0,0 +1,80 @@
+package ds205a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// ErrReadOnly indica un comando de control enviado por un handle de solo lectura
+var ErrReadOnly = errors.New("read-only handle: control commands are not allowed")
+
+// Observer es un handle de solo lectura sobre un Turnstile. Expone las
+// consultas de estado, contadores e información, y no tiene métodos de
+// control; SendRaw solo acepta comandos de consulta. Pensado para entregar
+// a componentes de monitoreo sin darles capacidad de abrir el torniquete
+type Observer struct {
+	t *Turnstile
+}
+
+// ReadOnly retorna un handle de solo lectura que comparte la conexión y el
+// bus con el torniquete
+func (t *Turnstile) ReadOnly() *Observer {
+	return &Observer{t: t}
+}
+
+// GetStatus obtiene el estado actual del torniquete
+func (o *Observer) GetStatus(ctx context.Context) (*Status, error) {
+	return o.t.GetStatus(ctx)
+}
+
+// GetDeviceInfo obtiene información del dispositivo
+func (o *Observer) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
+	return o.t.GetDeviceInfo(ctx)
+}
+
+// Fingerprint retorna la huella del equipo
+func (o *Observer) Fingerprint(ctx context.Context) (*Fingerprint, error) {
+	return o.t.Fingerprint(ctx)
+}
+
+// Stats retorna los contadores de comunicación
+func (o *Observer) Stats() Stats {
+	return o.t.Stats()
+}
+
+// State retorna el estado del torniquete visto por el controlador. No da
+// acceso a la máquina de estados para no permitir transiciones
+func (o *Observer) State() TurnstileState {
+	return o.t.StateMachine().State()
+}
+
+// OnTransition registra una función que se invoca con cada cambio de estado.
+// Los hooks corren con el bus ya liberado, así que pueden consultar el
+// estado con GetStatus, pero no deben bloquear: retrasan el retorno del
+// comando que produjo el cambio
+func (o *Observer) OnTransition(hook TransitionHook) {
+	o.t.StateMachine().OnTransition(hook)
+}
+
+// OnTransitionEvent es como OnTransition, pero el hook recibe el evento
+// con la huella del equipo
+func (o *Observer) OnTransitionEvent(hook TransitionEventHook) {
+	o.t.StateMachine().OnTransitionEvent(hook)
+}
+
+// Orientation retorna la orientación de instalación configurada
+func (o *Observer) Orientation() Orientation {
+	return o.t.Orientation()
+}
+
+// SendRaw envía un comando de consulta sin procesar. Cualquier comando que
+// no sea de consulta retorna ErrReadOnly sin tocar el bus
+func (o *Observer) SendRaw(ctx context.Context, cmd byte, data []byte) (*Response, error) {
+	if protocol.CommandType(cmd) != protocol.CmdGetStatus {
+		return nil, fmt.Errorf("%w: command 0x%02X", ErrReadOnly, cmd)
+	}
+	return o.t.SendRaw(ctx, cmd, data)
+}
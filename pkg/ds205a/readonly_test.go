@@ -0,0 +1,70 @@
+package ds205a
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestObserverHookPollsStatus(t *testing.T) {
+	ctx := context.Background()
+	turnstile, err := New("/dev/ttySIM", 0x01, 9600, time.Second, WithSimulatedResponses())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := turnstile.Open(); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer turnstile.Close()
+
+	observer := turnstile.ReadOnly()
+	polled := make(chan error, 1)
+	observer.OnTransition(func(from, to TurnstileState) {
+		if to == StateIdle {
+			_, err := observer.GetStatus(ctx)
+			polled <- err
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := turnstile.CloseGate(ctx)
+		if err == nil {
+			_, err = turnstile.GetStatus(ctx)
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("command: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("deadlock: hook could not poll status")
+	}
+	select {
+	case err := <-polled:
+		if err != nil {
+			t.Errorf("GetStatus from hook: %v", err)
+		}
+	default:
+		t.Fatal("hook did not run")
+	}
+}
+
+func TestObserverRejectsControl(t *testing.T) {
+	turnstile, err := New("/dev/ttySIM", 0x01, 9600, time.Second, WithSimulatedResponses())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := turnstile.Open(); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer turnstile.Close()
+
+	if _, err := turnstile.ReadOnly().SendRaw(context.Background(), byte(CmdLeftOpen), []byte{1}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("SendRaw(LeftOpen) error = %v, want ErrReadOnly", err)
+	}
+}
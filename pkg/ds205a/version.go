@@ -0,0 +1,43 @@
+package ds205a
+
+import (
+	"runtime/debug"
+
+	"github.com/dumacp/ds205a/internal/protocol"
+)
+
+// modulePath es la ruta del módulo usada para buscar la versión en build info
+const modulePath = "github.com/dumacp/ds205a"
+
+// Version retorna la versión del módulo ds205a compilado en el binario, según
+// la información de compilación de Go. Retorna "(devel)" cuando el módulo se
+// compila desde un árbol local sin versión
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+		if dep.Version != "" {
+			return dep.Version
+		}
+	}
+
+	return "(devel)"
+}
+
+// ProtocolRevision retorna el dialecto del protocolo implementado
+func ProtocolRevision() string {
+	return protocol.Revision
+}
@@ -0,0 +1,189 @@
+package i18n
+
+// Claves del catálogo
+const (
+	// Estado y información del dispositivo
+	StatusTitle      Key = "status.title"
+	StatusMachine    Key = "status.machine"
+	StatusVersion    Key = "status.version"
+	StatusFault      Key = "status.fault"
+	StatusGate       Key = "status.gate"
+	StatusGateState  Key = "status.gate_state"
+	StatusAlarm      Key = "status.alarm"
+	StatusInfrared   Key = "status.infrared"
+	StatusVoltage    Key = "status.voltage"
+	StatusLeftCount  Key = "status.left_count"
+	StatusRightCount Key = "status.right_count"
+	StatusEntryCount Key = "status.entry_count"
+	StatusExitCount  Key = "status.exit_count"
+	StatusReceivedAt Key = "status.received_at"
+	StatusLatency    Key = "status.latency"
+	InfoTitle        Key = "info.title"
+	InfoVersion      Key = "info.version"
+	InfoMachine      Key = "info.machine"
+	InfoFingerprint  Key = "info.fingerprint"
+	InfoQuirks       Key = "info.quirks"
+
+	// Progreso de comandos
+	CmdLeftOpen            Key = "cmd.left_open"
+	CmdLeftAlwaysOpen      Key = "cmd.left_always_open"
+	CmdRightOpen           Key = "cmd.right_open"
+	CmdRightAlwaysOpen     Key = "cmd.right_always_open"
+	CmdCloseGate           Key = "cmd.close_gate"
+	CmdForbidLeft          Key = "cmd.forbid_left"
+	CmdForbidRight         Key = "cmd.forbid_right"
+	CmdDisableRestrictions Key = "cmd.disable_restrictions"
+	CmdResetLeftCounters   Key = "cmd.reset_left_counters"
+	CmdResetRightCounters  Key = "cmd.reset_right_counters"
+	CmdSetParams           Key = "cmd.set_params"
+	CmdReset               Key = "cmd.reset"
+	CommandConfirmed       Key = "cmd.confirmed"
+
+	// Escenarios
+	ScenarioRunning Key = "scenario.running"
+	ScenarioPassed  Key = "scenario.passed"
+
+	// Actualizaciones
+	UpdateCurrent       Key = "update.current"
+	UpdateLatest        Key = "update.latest"
+	UpdateNotComparable Key = "update.not_comparable"
+	UpdateAvailable     Key = "update.available"
+	UpdateUpToDate      Key = "update.up_to_date"
+	UpdateDownloaded    Key = "update.downloaded"
+	UpdateFailed        Key = "update.failed"
+
+	// Fallos generales
+	OpenFailed    Key = "error.open_failed"
+	CommandFailed Key = "error.command_failed"
+	HintLabel     Key = "error.hint"
+
+	// Sugerencias de recuperación de fallos del puerto
+	HintPermissionDenied Key = "hint.permission_denied"
+	HintPortNotFound     Key = "hint.port_not_found"
+	HintPortBusy         Key = "hint.port_busy"
+	HintNotSerialPort    Key = "hint.not_serial_port"
+	HintDeviceUnplugged  Key = "hint.device_unplugged"
+	HintPortLocked       Key = "hint.port_locked"
+	HintPortLockedPID    Key = "hint.port_locked_pid"
+)
+
+var catalog = map[Locale]map[Key]string{
+	English: {
+		StatusTitle:      "Turnstile Status:",
+		StatusMachine:    "Machine Number",
+		StatusVersion:    "Version Number",
+		StatusFault:      "Fault Event",
+		StatusGate:       "Gate Status",
+		StatusGateState:  "Gate State",
+		StatusAlarm:      "Alarm Event",
+		StatusInfrared:   "Infrared Status",
+		StatusVoltage:    "Power Supply Voltage",
+		StatusLeftCount:  "Left Pedestrian Count",
+		StatusRightCount: "Right Pedestrian Count",
+		StatusEntryCount: "Entry Count",
+		StatusExitCount:  "Exit Count",
+		StatusReceivedAt: "Received At",
+		StatusLatency:    "Latency",
+		InfoTitle:        "Device Information:",
+		InfoVersion:      "Version",
+		InfoMachine:      "Machine Number",
+		InfoFingerprint:  "Fingerprint",
+		InfoQuirks:       "Protocol Quirks",
+
+		CmdLeftOpen:            "Opening left passage with value %d...",
+		CmdLeftAlwaysOpen:      "Setting left passage to always open...",
+		CmdRightOpen:           "Opening right passage with value %d...",
+		CmdRightAlwaysOpen:     "Setting right passage to always open...",
+		CmdCloseGate:           "Closing gate...",
+		CmdForbidLeft:          "Forbidding left passage...",
+		CmdForbidRight:         "Forbidding right passage...",
+		CmdDisableRestrictions: "Disabling passage restrictions...",
+		CmdResetLeftCounters:   "Resetting left counters...",
+		CmdResetRightCounters:  "Resetting right counters...",
+		CmdSetParams:           "Setting parameters with Menu %d y/o value %d...",
+		CmdReset:               "Resetting device...",
+		CommandConfirmed:       "Confirmed: gate status 0x%02X, latency %v, %d attempt(s)",
+
+		ScenarioRunning: "Running scenario %q (%d steps)...",
+		ScenarioPassed:  "Scenario passed: %d steps in %v",
+
+		UpdateCurrent:       "Current version: %s",
+		UpdateLatest:        "Latest release:  %s (%s)",
+		UpdateNotComparable: "Current version is not a release; cannot compare",
+		UpdateAvailable:     "An update is available",
+		UpdateUpToDate:      "Up to date",
+		UpdateDownloaded:    "Downloaded %s (sha256 %s matches %s; this checks integrity, not authenticity)",
+		UpdateFailed:        "Update check failed",
+
+		OpenFailed:    "Error opening device",
+		CommandFailed: "Command failed",
+		HintLabel:     "Hint",
+
+		HintPermissionDenied: "check the permissions of %s; on Linux add the user to the 'dialout' group (sudo usermod -aG dialout $USER) and log in again",
+		HintPortNotFound:     "check that the USB-RS485 adapter is connected and that %s is the right port (ls /dev/ttyUSB* /dev/ttyACM*)",
+		HintPortBusy:         "another process is using %[1]s; stop it before retrying (lsof %[1]s)",
+		HintNotSerialPort:    "%s is not a serial device; check the port name",
+		HintDeviceUnplugged:  "the serial adapter was disconnected; check the cable and USB connection, then reopen the device",
+		HintPortLocked:       "another process is driving %s; stop it before retrying",
+		HintPortLockedPID:    "process %[2]d is driving %[1]s; stop it before retrying (ps -p %[2]d)",
+	},
+	Spanish: {
+		StatusTitle:      "Estado del torniquete:",
+		StatusMachine:    "Número de máquina",
+		StatusVersion:    "Versión",
+		StatusFault:      "Evento de falla",
+		StatusGate:       "Estado de la puerta",
+		StatusGateState:  "Estado interpretado",
+		StatusAlarm:      "Evento de alarma",
+		StatusInfrared:   "Estado infrarrojo",
+		StatusVoltage:    "Voltaje de alimentación",
+		StatusLeftCount:  "Pasos izquierda",
+		StatusRightCount: "Pasos derecha",
+		StatusEntryCount: "Entradas",
+		StatusExitCount:  "Salidas",
+		StatusReceivedAt: "Recibido",
+		StatusLatency:    "Latencia",
+		InfoTitle:        "Información del dispositivo:",
+		InfoVersion:      "Versión",
+		InfoMachine:      "Número de máquina",
+		InfoFingerprint:  "Huella",
+		InfoQuirks:       "Particularidades del protocolo",
+
+		CmdLeftOpen:            "Abriendo paso izquierdo con valor %d...",
+		CmdLeftAlwaysOpen:      "Dejando el paso izquierdo siempre abierto...",
+		CmdRightOpen:           "Abriendo paso derecho con valor %d...",
+		CmdRightAlwaysOpen:     "Dejando el paso derecho siempre abierto...",
+		CmdCloseGate:           "Cerrando puerta...",
+		CmdForbidLeft:          "Prohibiendo paso izquierdo...",
+		CmdForbidRight:         "Prohibiendo paso derecho...",
+		CmdDisableRestrictions: "Deshabilitando restricciones de paso...",
+		CmdResetLeftCounters:   "Reiniciando contadores izquierdos...",
+		CmdResetRightCounters:  "Reiniciando contadores derechos...",
+		CmdSetParams:           "Configurando parámetros con menú %d y/o valor %d...",
+		CmdReset:               "Reiniciando dispositivo...",
+		CommandConfirmed:       "Confirmado: estado de la puerta 0x%02X, latencia %v, %d intento(s)",
+
+		ScenarioRunning: "Ejecutando escenario %q (%d pasos)...",
+		ScenarioPassed:  "Escenario exitoso: %d pasos en %v",
+
+		UpdateCurrent:       "Versión actual: %s",
+		UpdateLatest:        "Última versión: %s (%s)",
+		UpdateNotComparable: "La versión actual no es una versión publicada; no se puede comparar",
+		UpdateAvailable:     "Hay una actualización disponible",
+		UpdateUpToDate:      "Versión al día",
+		UpdateDownloaded:    "Descargado %s (sha256 %s coincide con %s; esto verifica integridad, no autenticidad)",
+		UpdateFailed:        "Falló la búsqueda de actualizaciones",
+
+		OpenFailed:    "Error abriendo el dispositivo",
+		CommandFailed: "Falló el comando",
+		HintLabel:     "Sugerencia",
+
+		HintPermissionDenied: "revise los permisos de %s; en Linux agregue el usuario al grupo 'dialout' (sudo usermod -aG dialout $USER) y vuelva a iniciar sesión",
+		HintPortNotFound:     "verifique que el adaptador USB-RS485 esté conectado y que %s sea el puerto correcto (ls /dev/ttyUSB* /dev/ttyACM*)",
+		HintPortBusy:         "otro proceso está usando %[1]s; deténgalo antes de reintentar (lsof %[1]s)",
+		HintNotSerialPort:    "%s no es un dispositivo serial; revise el nombre del puerto",
+		HintDeviceUnplugged:  "el adaptador serial se desconectó; revise el cable y la conexión USB y vuelva a abrir el dispositivo",
+		HintPortLocked:       "otro proceso está controlando %s; deténgalo antes de reintentar",
+		HintPortLockedPID:    "el proceso %[2]d está controlando %[1]s; deténgalo antes de reintentar (ps -p %[2]d)",
+	},
+}
@@ -0,0 +1,51 @@
+// Package i18n contiene el catálogo de mensajes de diagnóstico del DS205A
+// en inglés y español. Los errores de la librería siguen en inglés (para
+// logs y backends); el catálogo se usa al mostrar resultados a técnicos
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale identifica el idioma de los mensajes
+type Locale string
+
+const (
+	English Locale = "en"
+	Spanish Locale = "es"
+)
+
+// Key identifica un mensaje del catálogo
+type Key string
+
+// ParseLocale interpreta códigos como "es", "es_CO.UTF-8" o "en-US"
+func ParseLocale(s string) (Locale, error) {
+	lang := strings.ToLower(s)
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	switch Locale(lang) {
+	case English, Spanish:
+		return Locale(lang), nil
+	default:
+		return "", fmt.Errorf("unsupported locale: %q (valid: en, es)", s)
+	}
+}
+
+// Text retorna el mensaje de la clave en el idioma indicado, formateado con
+// args. Si el idioma no tiene la clave usa el inglés y, en último caso, la
+// clave misma
+func Text(locale Locale, key Key, args ...interface{}) string {
+	msg, ok := catalog[locale][key]
+	if !ok {
+		msg, ok = catalog[English][key]
+	}
+	if !ok {
+		msg = string(key)
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
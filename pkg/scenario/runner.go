@@ -0,0 +1,208 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dumacp/ds205a/pkg/ds205a"
+)
+
+// Target es el dispositivo contra el que se ejecuta el escenario.
+// *ds205a.Turnstile implementa esta interfaz
+type Target interface {
+	GetStatus(ctx context.Context) (*ds205a.Status, error)
+	LeftOpen(ctx context.Context, value uint8) (*ds205a.CommandResult, error)
+	LeftAlwaysOpen(ctx context.Context) error
+	RightOpen(ctx context.Context, value uint8) (*ds205a.CommandResult, error)
+	RightAlwaysOpen(ctx context.Context) error
+	CloseGate(ctx context.Context) (*ds205a.CommandResult, error)
+	ForbiddenLeftPassage(ctx context.Context) error
+	ForbiddenRightPassage(ctx context.Context) error
+	DisablePassageRestrictions(ctx context.Context) error
+	ResetLeftCounters(ctx context.Context) error
+	ResetRightCounters(ctx context.Context) error
+	Reset(ctx context.Context) error
+	SetParameters(ctx context.Context, value1 uint8, value2 uint8) error
+}
+
+// StepResult contiene el resultado de un paso ejecutado
+type StepResult struct {
+	Index    int           // Índice del paso (desde 1)
+	Name     string        // Nombre del paso
+	Duration time.Duration // Duración de la ejecución
+	Err      error         // Error del paso (nil si fue exitoso)
+}
+
+// Report resume la ejecución de un escenario
+type Report struct {
+	Scenario string       // Nombre del escenario
+	Steps    []StepResult // Resultados de los pasos ejecutados
+	Duration time.Duration
+}
+
+// Passed indica si todos los pasos ejecutados fueron exitosos
+func (r *Report) Passed() bool {
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Runner ejecuta escenarios contra un dispositivo
+type Runner struct {
+	target      Target
+	stepTimeout time.Duration
+	onStep      func(StepResult)
+}
+
+// NewRunner crea un ejecutor de escenarios. stepTimeout se usa para los
+// pasos que no definen su propio timeout
+func NewRunner(target Target, stepTimeout time.Duration) *Runner {
+	return &Runner{
+		target:      target,
+		stepTimeout: stepTimeout,
+	}
+}
+
+// OnStep registra una función que se invoca al terminar cada paso
+func (r *Runner) OnStep(fn func(StepResult)) {
+	r.onStep = fn
+}
+
+// Run ejecuta los pasos en orden y se detiene en el primer error
+func (r *Runner) Run(ctx context.Context, sc *Scenario) (*Report, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Scenario: sc.Name}
+	start := time.Now()
+	defer func() { report.Duration = time.Since(start) }()
+
+	for i, step := range sc.Steps {
+		stepStart := time.Now()
+		err := r.runStep(ctx, step)
+		result := StepResult{
+			Index:    i + 1,
+			Name:     step.label(),
+			Duration: time.Since(stepStart),
+			Err:      err,
+		}
+		report.Steps = append(report.Steps, result)
+		if r.onStep != nil {
+			r.onStep(result)
+		}
+		if err != nil {
+			return report, fmt.Errorf("step %d (%s): %w", result.Index, result.Name, err)
+		}
+	}
+
+	return report, nil
+}
+
+// runStep ejecuta un único paso
+func (r *Runner) runStep(ctx context.Context, step Step) error {
+	if step.Wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(step.Wait):
+			return nil
+		}
+	}
+
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = r.stepTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if step.Expect != nil {
+		status, err := r.target.GetStatus(ctx)
+		if err != nil {
+			return err
+		}
+		return step.Expect.check(status)
+	}
+
+	return r.runCommand(ctx, step)
+}
+
+// runCommand despacha un comando al dispositivo
+func (r *Runner) runCommand(ctx context.Context, step Step) error {
+	t := r.target
+	switch step.Command {
+	case CmdStatus:
+		_, err := t.GetStatus(ctx)
+		return err
+	case CmdLeftOpen:
+		_, err := t.LeftOpen(ctx, *step.Value)
+		return err
+	case CmdLeftAlwaysOpen:
+		return t.LeftAlwaysOpen(ctx)
+	case CmdRightOpen:
+		_, err := t.RightOpen(ctx, *step.Value)
+		return err
+	case CmdRightAlwaysOpen:
+		return t.RightAlwaysOpen(ctx)
+	case CmdCloseGate:
+		_, err := t.CloseGate(ctx)
+		return err
+	case CmdForbidLeft:
+		return t.ForbiddenLeftPassage(ctx)
+	case CmdForbidRight:
+		return t.ForbiddenRightPassage(ctx)
+	case CmdDisableRestrictions:
+		return t.DisablePassageRestrictions(ctx)
+	case CmdResetLeftCounters:
+		return t.ResetLeftCounters(ctx)
+	case CmdResetRightCounters:
+		return t.ResetRightCounters(ctx)
+	case CmdSetParams:
+		return t.SetParameters(ctx, *step.Value, *step.Value2)
+	case CmdReset:
+		return t.Reset(ctx)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, step.Command)
+	}
+}
+
+// check compara el estado recibido con los valores esperados
+func (e *StatusExpect) check(status *ds205a.Status) error {
+	var mismatches []string
+
+	checkByte := func(name string, want *uint8, got uint8) {
+		if want != nil && *want != got {
+			mismatches = append(mismatches, fmt.Sprintf("%s: got 0x%02X, want 0x%02X", name, got, *want))
+		}
+	}
+	checkCount := func(name string, want *uint32, got uint32) {
+		if want != nil && *want != got {
+			mismatches = append(mismatches, fmt.Sprintf("%s: got %d, want %d", name, got, *want))
+		}
+	}
+
+	checkByte("fault_event", e.FaultEvent, status.FaultEvent)
+	checkByte("gate_status", e.GateStatus, status.GateStatus)
+	checkByte("alarm_event", e.AlarmEvent, status.AlarmEvent)
+	checkByte("infrared_status", e.InfraredStatus, status.InfraredStatus)
+	checkCount("left_count", e.LeftCount, status.LeftPedestrianCount)
+	checkCount("right_count", e.RightCount, status.RightPedestrianCount)
+	if e.MinVoltage != nil && status.PowerSupplyVoltage < *e.MinVoltage {
+		mismatches = append(mismatches, fmt.Sprintf("voltage: got %d, want >= %d",
+			status.PowerSupplyVoltage, *e.MinVoltage))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", ErrAssertionFailed, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidScenario  = errors.New("invalid scenario")
+	ErrUnknownCommand   = errors.New("unknown scenario command")
+	ErrAssertionFailed  = errors.New("status assertion failed")
+	ErrMissingParameter = errors.New("missing command parameter")
+)
+
+// Comandos aceptados en los pasos del escenario (mismos nombres que el CLI)
+const (
+	CmdStatus              = "status"
+	CmdLeftOpen            = "left-open"
+	CmdLeftAlwaysOpen      = "left-always-open"
+	CmdRightOpen           = "right-open"
+	CmdRightAlwaysOpen     = "right-always-open"
+	CmdCloseGate           = "close-gate"
+	CmdForbidLeft          = "forbid-left"
+	CmdForbidRight         = "forbid-right"
+	CmdDisableRestrictions = "disable-restrictions"
+	CmdResetLeftCounters   = "reset-left-counters"
+	CmdResetRightCounters  = "reset-right-counters"
+	CmdSetParams           = "set-params"
+	CmdReset               = "reset"
+)
+
+// Scenario representa un procedimiento declarativo de pruebas o puesta en marcha
+type Scenario struct {
+	Name        string `yaml:"name"`        // Nombre del escenario
+	Description string `yaml:"description"` // Descripción opcional
+	Steps       []Step `yaml:"steps"`       // Pasos en orden de ejecución
+}
+
+// Step representa un paso del escenario. Cada paso define exactamente una
+// acción: un comando, una espera o una verificación de estado
+type Step struct {
+	Name    string         `yaml:"name"`          // Nombre descriptivo (opcional)
+	Command string         `yaml:"command"`       // Comando a ejecutar
+	Value   *uint8         `yaml:"value"`         // Valor para comandos que lo requieren
+	Value2  *uint8         `yaml:"value2"`        // Segundo valor (set-params)
+	Wait    time.Duration  `yaml:"wait"`          // Espera (ej: "500ms", "2s")
+	Expect  *StatusExpect  `yaml:"expect_status"` // Verificación del estado del dispositivo
+	Timeout time.Duration  `yaml:"timeout"`       // Timeout del paso (opcional)
+	Extra   map[string]any `yaml:",inline"`       // Campos desconocidos (se rechazan al validar)
+}
+
+// StatusExpect contiene los valores esperados del estado. Los campos nulos no se verifican
+type StatusExpect struct {
+	FaultEvent     *uint8  `yaml:"fault_event"`
+	GateStatus     *uint8  `yaml:"gate_status"`
+	AlarmEvent     *uint8  `yaml:"alarm_event"`
+	InfraredStatus *uint8  `yaml:"infrared_status"`
+	MinVoltage     *uint8  `yaml:"min_voltage"`
+	LeftCount      *uint32 `yaml:"left_count"`
+	RightCount     *uint32 `yaml:"right_count"`
+}
+
+// Load lee y valida un escenario desde un archivo YAML
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodifica y valida un escenario en formato YAML
+func Parse(data []byte) (*Scenario, error) {
+	var sc Scenario
+	if err := yaml.Unmarshal(data, &sc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScenario, err)
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
+
+// Validate verifica que cada paso defina una única acción válida
+func (sc *Scenario) Validate() error {
+	if len(sc.Steps) == 0 {
+		return fmt.Errorf("%w: no steps defined", ErrInvalidScenario)
+	}
+
+	for i, step := range sc.Steps {
+		for key := range step.Extra {
+			return fmt.Errorf("%w: step %d: unsupported field %q", ErrInvalidScenario, i+1, key)
+		}
+
+		actions := 0
+		if step.Command != "" {
+			actions++
+		}
+		if step.Wait > 0 {
+			actions++
+		}
+		if step.Expect != nil {
+			actions++
+		}
+		if actions != 1 {
+			return fmt.Errorf("%w: step %d must define exactly one of command, wait or expect_status",
+				ErrInvalidScenario, i+1)
+		}
+
+		if step.Command != "" {
+			if err := validateCommand(step); err != nil {
+				return fmt.Errorf("%w: step %d: %v", ErrInvalidScenario, i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCommand verifica el nombre y los parámetros de un comando
+func validateCommand(step Step) error {
+	switch step.Command {
+	case CmdLeftOpen, CmdRightOpen:
+		if step.Value == nil {
+			return fmt.Errorf("%w: %s requires value", ErrMissingParameter, step.Command)
+		}
+	case CmdSetParams:
+		if step.Value == nil || step.Value2 == nil {
+			return fmt.Errorf("%w: %s requires value and value2", ErrMissingParameter, step.Command)
+		}
+	case CmdStatus, CmdLeftAlwaysOpen, CmdRightAlwaysOpen, CmdCloseGate,
+		CmdForbidLeft, CmdForbidRight, CmdDisableRestrictions,
+		CmdResetLeftCounters, CmdResetRightCounters, CmdReset:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, step.Command)
+	}
+	return nil
+}
+
+// label retorna el nombre del paso para reportes
+func (s Step) label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	switch {
+	case s.Command != "":
+		return s.Command
+	case s.Wait > 0:
+		return fmt.Sprintf("wait %s", s.Wait)
+	default:
+		return "expect_status"
+	}
+}